@@ -0,0 +1,124 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"crumb/internal/config"
+	"crumb/internal/storage"
+)
+
+// runAdd saves a crumb from flags, files or stdin without the TUI
+func runAdd(cfg *config.Config, args []string, toolOverride, titleOverride string) error {
+	fs := flag.NewFlagSet("add", flag.ContinueOnError)
+
+	var (
+		tags       stringList
+		promptText string
+		promptFile string
+		outputFile string
+		tool       string
+		title      string
+		overwrite  bool
+	)
+	fs.StringVar(&promptText, "prompt", "", "prompt text")
+	fs.StringVar(&promptFile, "prompt-file", "", "read the prompt from a file ('-' for stdin)")
+	fs.StringVar(&outputFile, "output-file", "", "read the LLM output from a file ('-' for stdin)")
+	fs.Var(&tags, "tag", "tag to add (repeatable or comma-separated)")
+	fs.StringVar(&tool, "tool", toolOverride, "tool used (defaults to default_tool)")
+	fs.StringVar(&title, "title", titleOverride, "title (auto-generated from the prompt if empty)")
+	fs.BoolVar(&overwrite, "overwrite", false, "replace a crumb with the same date and title instead of numbering the new file")
+	addCommonFlags(fs)
+
+	positional, err := parseInterspersed(fs, args)
+	if err != nil {
+		return err
+	}
+
+	// `crumb add -` reads the prompt from stdin
+	switch {
+	case len(positional) == 1 && positional[0] == "-":
+		promptFile = "-"
+	case len(positional) > 0:
+		return fmt.Errorf("unexpected arguments: %s (use --prompt, --prompt-file or '-')", strings.Join(positional, " "))
+	}
+
+	if promptFile == "-" && outputFile == "-" {
+		return fmt.Errorf("only one of the prompt or output can be read from stdin")
+	}
+
+	prompt := promptText
+	if promptFile != "" {
+		if promptText != "" {
+			return fmt.Errorf("use either --prompt or --prompt-file, not both")
+		}
+		text, err := readInput(promptFile)
+		if err != nil {
+			return fmt.Errorf("failed to read prompt: %w", err)
+		}
+		prompt = text
+	}
+	if strings.TrimSpace(prompt) == "" {
+		return fmt.Errorf("prompt is required (use --prompt, --prompt-file or pipe it to 'crumb add -')")
+	}
+
+	var output string
+	if outputFile != "" {
+		text, err := readInput(outputFile)
+		if err != nil {
+			return fmt.Errorf("failed to read output: %w", err)
+		}
+		output = text
+	}
+
+	if tool == "" {
+		tool = cfg.DefaultTool
+	} else {
+		warnUnknownTool(cfg, tool)
+	}
+
+	dir, err := crumbsDir(cfg)
+	if err != nil {
+		return err
+	}
+
+	crumb := storage.NewCrumb(prompt, output, title, tool, tags, cfg.Identity())
+	if cfg.Git.ContextEnabled() {
+		crumb.Git = storage.CaptureGitContext(".", dir)
+	}
+	s, err := newStorage(cfg, dir)
+	if err != nil {
+		return err
+	}
+	s.Overwrite = overwrite
+	path, err := s.SaveCrumb(crumb)
+	var commitErr *storage.CommitError
+	if errors.As(err, &commitErr) {
+		fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+	} else if err != nil {
+		return fmt.Errorf("failed to save crumb: %w", err)
+	}
+
+	fmt.Println(path)
+	return nil
+}
+
+// readInput reads a whole file, or stdin when path is "-", trimming
+// surrounding blank lines
+func readInput(path string) (string, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.Trim(string(data), "\r\n"), nil
+}
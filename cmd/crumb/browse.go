@@ -0,0 +1,40 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"crumb/internal/config"
+	"crumb/internal/tui"
+)
+
+// runBrowse launches the library browser
+func runBrowse(cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("browse", flag.ContinueOnError)
+	addCommonFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	dir, err := crumbsDir(cfg)
+	if err != nil {
+		return err
+	}
+	store, err := newStorage(cfg, dir)
+	if err != nil {
+		return err
+	}
+
+	browser, err := tui.NewBrowser(cfg, store)
+	if err != nil {
+		return fmt.Errorf("failed to load crumbs: %w", err)
+	}
+
+	p := tea.NewProgram(browser, tea.WithAltScreen())
+	if _, err := p.Run(); err != nil {
+		return fmt.Errorf("TUI error: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"crumb/internal/clipboard"
+	"crumb/internal/config"
+)
+
+// runCopy copies a crumb's prompt to the clipboard
+func runCopy(cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("copy", flag.ContinueOnError)
+	withOutput := fs.Bool("with-output", false, "also copy the recorded output")
+	printOnly := fs.Bool("print", false, "print to stdout instead of copying")
+	addCommonFlags(fs)
+
+	positional, err := parseInterspersed(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) == 0 {
+		return fmt.Errorf("usage: crumb copy <file|query> [--with-output] [--print]")
+	}
+
+	crumb, err := resolveCrumb(cfg, strings.Join(positional, " "))
+	if err != nil {
+		return err
+	}
+
+	text := crumb.CopyText(*withOutput)
+	if *printOnly {
+		fmt.Println(text)
+		return nil
+	}
+
+	method, err := clipboard.Copy(text)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "copied %q to %s\n", crumb.Title, method)
+	return nil
+}
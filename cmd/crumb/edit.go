@@ -0,0 +1,41 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"crumb/internal/config"
+	"crumb/internal/tui"
+)
+
+// runEdit loads an existing crumb into the TUI form and rewrites it on save
+func runEdit(cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("edit", flag.ContinueOnError)
+	addCommonFlags(fs)
+	positional, err := parseInterspersed(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) == 0 {
+		return fmt.Errorf("usage: crumb edit <file|query>")
+	}
+
+	crumb, err := resolveCrumb(cfg, strings.Join(positional, " "))
+	if err != nil {
+		return err
+	}
+	store, err := newStorage(cfg, filepath.Dir(crumb.Path))
+	if err != nil {
+		return err
+	}
+
+	p := tea.NewProgram(tui.NewEdit(cfg, store, crumb), tea.WithAltScreen())
+	if _, err := p.Run(); err != nil {
+		return fmt.Errorf("TUI error: %w", err)
+	}
+	return nil
+}
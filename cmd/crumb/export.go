@@ -0,0 +1,40 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"crumb/internal/config"
+	"crumb/internal/messages"
+)
+
+// runExport prints a crumb as chat message JSON for the OpenAI or
+// Anthropic APIs
+func runExport(cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	format := fs.String("format", messages.OpenAI, "output format: "+strings.Join(messages.Formats, " or "))
+	noOutput := fs.Bool("no-output", false, "leave out the recorded output (assistant message)")
+	addCommonFlags(fs)
+
+	positional, err := parseInterspersed(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) == 0 {
+		return fmt.Errorf("usage: crumb export <file|query> [--format %s]", strings.Join(messages.Formats, "|"))
+	}
+
+	crumb, err := resolveCrumb(cfg, strings.Join(positional, " "))
+	if err != nil {
+		return err
+	}
+
+	data, err := messages.Marshal(crumb, *format, !*noOutput)
+	if err != nil {
+		return err
+	}
+	_, err = os.Stdout.Write(data)
+	return err
+}
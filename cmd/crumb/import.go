@@ -0,0 +1,197 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"crumb/internal/config"
+	"crumb/internal/importer"
+	"crumb/internal/messages"
+	"crumb/internal/storage"
+	"crumb/internal/tui"
+)
+
+const importUsage = `usage: crumb import claude-code [session.jsonl...]
+       crumb import aider [.aider.chat.history.md]
+       crumb import openai-messages|anthropic-messages [file.json|-]`
+
+// runImport saves conversation turns logged by an AI tool as crumbs,
+// letting the user pick which ones unless --all is given
+func runImport(cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	var tags stringList
+	fs.Var(&tags, "tag", "tag every imported crumb (repeatable)")
+	all := fs.Bool("all", false, "import every new turn without asking")
+	limit := fs.Int("limit", 50, "offer at most this many recent turns (0 for all)")
+	addCommonFlags(fs)
+
+	positional, err := parseInterspersed(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) == 0 {
+		return errors.New(importUsage)
+	}
+
+	source, files := positional[0], positional[1:]
+	var candidates []importer.Candidate
+	switch source {
+	case "claude-code", "claude":
+		source = importer.ClaudeCodeTool
+		candidates, err = loadClaudeCode(files)
+	case "aider":
+		source = importer.AiderTool
+		candidates, err = loadAider(files)
+	case messages.OpenAI:
+		source = importer.OpenAITool
+		candidates, err = loadMessages(files, source)
+	case messages.Anthropic:
+		source = importer.AnthropicTool
+		candidates, err = loadMessages(files, source)
+	default:
+		return fmt.Errorf("unknown import source: %s\n%s", source, importUsage)
+	}
+	if err != nil {
+		return err
+	}
+
+	dir, err := crumbsDir(cfg)
+	if err != nil {
+		return err
+	}
+	store, err := newStorage(cfg, dir)
+	if err != nil {
+		return err
+	}
+
+	// turns imported before are left out
+	existing, err := store.List()
+	if err != nil {
+		return fmt.Errorf("failed to list crumbs: %w", err)
+	}
+	candidates = importer.SkipImported(candidates, existing)
+	if *limit > 0 && len(candidates) > *limit {
+		candidates = candidates[:*limit]
+	}
+	if len(candidates) == 0 {
+		fmt.Fprintln(os.Stderr, "nothing new to import")
+		return nil
+	}
+
+	chosen := candidates
+	if !*all {
+		if !isTerminal(os.Stdin) || !isTerminal(os.Stderr) {
+			return fmt.Errorf("%d turns to import: run in a terminal to choose, or pass --all", len(candidates))
+		}
+		p := tea.NewProgram(tui.NewImportPicker(source, candidates), tea.WithOutput(os.Stderr), tea.WithAltScreen())
+		final, err := p.Run()
+		if err != nil {
+			return fmt.Errorf("TUI error: %w", err)
+		}
+		picker := final.(tui.ImportPicker)
+		if !picker.Confirmed() {
+			return fmt.Errorf("cancelled")
+		}
+		chosen = picker.Chosen()
+	}
+
+	return saveImported(cfg, store, chosen, tags)
+}
+
+// loadClaudeCode reads the given session logs, or every session Claude
+// Code recorded for the working directory
+func loadClaudeCode(files []string) ([]importer.Candidate, error) {
+	if len(files) == 0 {
+		projects, err := importer.ClaudeCodeProjectsDir()
+		if err != nil {
+			return nil, err
+		}
+		cwd, err := os.Getwd()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get current directory: %w", err)
+		}
+		if files, err = importer.ClaudeCodeSessions(projects, cwd); err != nil {
+			return nil, err
+		}
+	}
+	return importer.LoadClaudeCode(files...)
+}
+
+// loadAider reads the given chat histories, or the one in the root of the
+// enclosing git repository (or the working directory)
+func loadAider(files []string) ([]importer.Candidate, error) {
+	if len(files) == 0 {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get current directory: %w", err)
+		}
+		root := config.GitRoot(cwd)
+		if root == "" {
+			root = cwd
+		}
+		files = []string{filepath.Join(root, importer.AiderHistoryFile)}
+	}
+
+	var all []importer.Candidate
+	for _, file := range files {
+		candidates, err := importer.LoadAider(file)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, candidates...)
+	}
+	return all, nil
+}
+
+// loadMessages reads messages JSON files, or stdin when none (or "-") is
+// given
+func loadMessages(files []string, tool string) ([]importer.Candidate, error) {
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+
+	var all []importer.Candidate
+	for _, file := range files {
+		var data []byte
+		var err error
+		if file == "-" {
+			data, err = io.ReadAll(os.Stdin)
+		} else {
+			data, err = os.ReadFile(file)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read messages: %w", err)
+		}
+
+		candidates, err := importer.ParseMessages(data, tool)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		all = append(all, candidates...)
+	}
+	return all, nil
+}
+
+// saveImported writes each chosen candidate as a crumb, printing its path
+func saveImported(cfg *config.Config, store *storage.MarkdownStorage, chosen []importer.Candidate, tags []string) error {
+	author := cfg.Identity()
+	for _, c := range chosen {
+		path, err := store.SaveCrumb(c.Crumb(tags, author))
+		var commitErr *storage.CommitError
+		if errors.As(err, &commitErr) {
+			fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+		} else if err != nil {
+			return fmt.Errorf("failed to save crumb: %w", err)
+		}
+		fmt.Println(path)
+	}
+
+	fmt.Fprintf(os.Stderr, "imported %d crumb(s)\n", len(chosen))
+	return nil
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"crumb/internal/config"
+	"crumb/internal/storage"
+)
+
+// stringList is a repeatable flag that also accepts comma-separated values
+type stringList []string
+
+func (s *stringList) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *stringList) Set(value string) error {
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			*s = append(*s, v)
+		}
+	}
+	return nil
+}
+
+// parseInterspersed parses flags that may appear before or after positional
+// arguments (e.g. `crumb add - --tag x`) and returns the positionals
+func parseInterspersed(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// runList prints a table of crumbs matching the given filters
+func runList(cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("list", flag.ContinueOnError)
+
+	var (
+		tags    stringList
+		filter  storage.Filter
+		since   string
+		until   string
+		sortBy  string
+		reverse bool
+	)
+	fs.Var(&tags, "tag", "only crumbs with this tag (repeatable)")
+	fs.StringVar(&filter.Tool, "tool", "", "only crumbs captured with this tool")
+	fs.StringVar(&filter.Author, "author", "", "only crumbs whose author contains this text")
+	fs.StringVar(&since, "since", "", "only crumbs on or after this date (YYYY-MM-DD or 7d, 2w)")
+	fs.StringVar(&until, "until", "", "only crumbs on or before this date (YYYY-MM-DD or 7d, 2w)")
+	fs.StringVar(&sortBy, "sort", "date", "sort by date, title, author or tool")
+	fs.BoolVar(&reverse, "reverse", false, "reverse sort order")
+	addCommonFlags(fs)
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	filter.Tags = tags
+
+	var err error
+	if filter.Since, err = parseDateFlag(since, false); err != nil {
+		return fmt.Errorf("invalid --since: %w", err)
+	}
+	if filter.Until, err = parseDateFlag(until, true); err != nil {
+		return fmt.Errorf("invalid --until: %w", err)
+	}
+
+	dir, err := crumbsDir(cfg)
+	if err != nil {
+		return err
+	}
+
+	crumbs, err := listCrumbs(cfg, dir)
+	if err != nil {
+		return fmt.Errorf("failed to list crumbs: %w", err)
+	}
+
+	crumbs = storage.FilterCrumbs(crumbs, filter)
+	if err := storage.SortCrumbs(crumbs, sortBy, reverse); err != nil {
+		return err
+	}
+
+	if len(crumbs) == 0 {
+		fmt.Fprintln(os.Stderr, "no crumbs found")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "DATE\tAUTHOR\tTOOL\tTAGS\tTITLE")
+	for _, c := range crumbs {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+			c.Date.Format("2006-01-02"),
+			c.Author,
+			c.Tool,
+			strings.Join(c.Tags, ", "),
+			c.Title,
+		)
+	}
+	return w.Flush()
+}
+
+// parseDateFlag accepts YYYY-MM-DD, RFC3339 or a relative age like 7d or 2w.
+// Date-only upper bounds extend to the end of that day.
+func parseDateFlag(value string, endOfDay bool) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	if n := len(value); n > 1 && (value[n-1] == 'd' || value[n-1] == 'w') {
+		if count, err := strconv.Atoi(value[:n-1]); err == nil {
+			days := count
+			if value[n-1] == 'w' {
+				days *= 7
+			}
+			return time.Now().AddDate(0, 0, -days), nil
+		}
+	}
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	t, err := time.ParseInLocation("2006-01-02", value, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("expected YYYY-MM-DD, RFC3339 or a relative age like 7d, got %q", value)
+	}
+	if endOfDay {
+		t = t.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	}
+	return t, nil
+}
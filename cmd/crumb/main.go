@@ -1,23 +1,31 @@
 package main
 
 import (
+	"errors"
 	"flag"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
 
 	"github.com/adrg/xdg"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/glamour"
+	"gopkg.in/yaml.v3"
 	"crumb/internal/config"
 	"crumb/internal/readme"
+	"crumb/internal/storage"
 	"crumb/internal/tui"
 )
 
 var version = "dev"
 
+// flags shared by every command, see addCommonFlags
+var (
+	dirFlag    string
+	commitFlag bool
+)
+
 func main() {
 	if err := run(); err != nil {
 		fmt.Fprintf(os.Stderr, "error: %v\n", err)
@@ -28,21 +36,25 @@ func main() {
 func run() error {
 	// define flags
 	var (
-		toolFlag    string
-		titleFlag   string
-		stayFlag    bool
-		versionFlag bool
-		helpFlag    bool
+		toolFlag     string
+		titleFlag    string
+		templateFlag string
+		stayFlag     bool
+		versionFlag  bool
+		helpFlag     bool
 	)
 
 	flag.StringVar(&toolFlag, "tool", "", "override default tool for this session")
 	flag.StringVar(&toolFlag, "t", "", "override default tool for this session (shorthand)")
 	flag.StringVar(&titleFlag, "title", "", "set title for the prompt")
+	flag.StringVar(&templateFlag, "template", "", "start from a starter template")
+	flag.StringVar(&templateFlag, "T", "", "start from a starter template (shorthand)")
 	flag.BoolVar(&stayFlag, "stay", false, "don't exit after save (capture multiple prompts)")
 	flag.BoolVar(&versionFlag, "version", false, "show version")
 	flag.BoolVar(&versionFlag, "v", false, "show version (shorthand)")
 	flag.BoolVar(&helpFlag, "help", false, "show help")
 	flag.BoolVar(&helpFlag, "h", false, "show help (shorthand)")
+	addCommonFlags(flag.CommandLine)
 
 	flag.Usage = printUsage
 	flag.Parse()
@@ -76,13 +88,31 @@ func run() error {
 	switch command {
 	case "":
 		// default: launch TUI
-		return runTUI(cfg, toolFlag, titleFlag, stayFlag)
+		return runTUI(cfg, toolFlag, titleFlag, templateFlag, stayFlag)
+	case "add":
+		return runAdd(cfg, args[1:], toolFlag, titleFlag)
+	case "browse":
+		return runBrowse(cfg, args[1:])
+	case "copy", "cp":
+		return runCopy(cfg, args[1:])
+	case "use":
+		return runUse(cfg, args[1:])
+	case "edit":
+		return runEdit(cfg, args[1:])
+	case "import":
+		return runImport(cfg, args[1:])
+	case "export":
+		return runExport(cfg, args[1:])
+	case "list", "ls":
+		return runList(cfg, args[1:])
+	case "search":
+		return runSearch(cfg, args[1:])
 	case "readme":
-		return runReadme(cfg)
+		return runReadme(cfg, args[1:])
 	case "config":
-		return runConfig()
+		return runConfig(cfg, args[1:])
 	case "init":
-		return runInit(cfg)
+		return runInit(cfg, args[1:])
 	default:
 		// check if it's a markdown file
 		if strings.HasSuffix(command, ".md") {
@@ -93,32 +123,30 @@ func run() error {
 }
 
 // runTUI launches the TUI to capture a new prompt
-func runTUI(cfg *config.Config, toolOverride, titleOverride string, stay bool) error {
+func runTUI(cfg *config.Config, toolOverride, titleOverride, template string, stay bool) error {
 	// determine which tool to use
 	selectedTool := cfg.DefaultTool
 	if toolOverride != "" {
 		selectedTool = toolOverride
+		warnUnknownTool(cfg, toolOverride)
+	}
 
-		// validate that tool is in known tools list
-		allTools := config.GetAllTools(cfg)
-		found := false
-		for _, t := range allTools {
-			if t == toolOverride {
-				found = true
-				break
-			}
-		}
-
-		if !found {
-			// warn but still allow unknown tool
-			fmt.Fprintf(os.Stderr, "warning: tool '%s' is not in known tools list (built-in + custom)\n", toolOverride)
-			fmt.Fprintf(os.Stderr, "known tools: %v\n", allTools)
-			fmt.Fprintf(os.Stderr, "continuing anyway...\n\n")
-		}
+	dir, err := crumbsDir(cfg)
+	if err != nil {
+		return err
+	}
+	store, err := newStorage(cfg, dir)
+	if err != nil {
+		return err
 	}
 
 	// create and run TUI model with tool pre-selected
-	model := tui.New(cfg, selectedTool, titleOverride, stay)
+	model := tui.New(cfg, store, selectedTool, titleOverride, stay)
+	if template != "" {
+		if err := model.ApplyTemplate(template); err != nil {
+			return err
+		}
+	}
 	p := tea.NewProgram(model, tea.WithAltScreen())
 	if _, err := p.Run(); err != nil {
 		return fmt.Errorf("TUI error: %w", err)
@@ -126,40 +154,105 @@ func runTUI(cfg *config.Config, toolOverride, titleOverride string, stay bool) e
 	return nil
 }
 
+// warnUnknownTool warns (but still allows) tools missing from the known tools list
+func warnUnknownTool(cfg *config.Config, tool string) {
+	allTools := config.GetAllTools(cfg)
+	for _, t := range allTools {
+		if t == tool {
+			return
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "warning: tool '%s' is not in known tools list (built-in + custom)\n", tool)
+	fmt.Fprintf(os.Stderr, "known tools: %v\n", allTools)
+	fmt.Fprintf(os.Stderr, "continuing anyway...\n\n")
+}
+
 // runReadme generates/updates the README.md in the prompts directory
-func runReadme(cfg *config.Config) error {
-	// get current working directory
-	cwd, err := os.Getwd()
-	if err != nil {
-		return fmt.Errorf("failed to get current directory: %w", err)
+func runReadme(cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("readme", flag.ContinueOnError)
+	var groups stringList
+	fs.Var(&groups, "group", "add a section grouped by tag, tool or author (repeatable)")
+	tmpl := fs.String("template", cfg.ReadmeTemplate, "text/template file for the index layout")
+	check := fs.Bool("check", false, "exit non-zero with a diff if README.md is out of date (writes nothing)")
+	addCommonFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if len(groups) == 0 {
+		groups = cfg.ReadmeGroups
 	}
 
-	// prompts directory is relative to cwd
-	promptsDir := filepath.Join(cwd, cfg.OutputDir)
+	promptsDir, err := crumbsDir(cfg)
+	if err != nil {
+		return err
+	}
 
 	// check if directory exists
 	if _, err := os.Stat(promptsDir); os.IsNotExist(err) {
 		return fmt.Errorf("prompts directory does not exist: %s (run 'crumb init' first)", promptsDir)
 	}
 
-	// generate README content
-	content, err := readme.Generate(promptsDir)
+	committer, err := newCommitter(cfg)
 	if err != nil {
-		return fmt.Errorf("failed to generate README: %w", err)
+		return err
 	}
+	gen := readme.NewGenerator(promptsDir, readme.Options{
+		Groups:   groups,
+		Template: *tmpl,
+		Git:      committer,
+		Roster:   cfg.Roster,
+	})
 
-	// write README.md
 	readmePath := filepath.Join(promptsDir, "README.md")
-	if err := os.WriteFile(readmePath, []byte(content), 0644); err != nil {
-		return fmt.Errorf("failed to write README: %w", err)
+	if *check {
+		content, err := gen.Content()
+		if err != nil {
+			return fmt.Errorf("failed to generate README: %w", err)
+		}
+		return checkReadme(readmePath, content)
+	}
+
+	// write README.md (under the directory lock), committing it if enabled
+	var commitErr *storage.CommitError
+	if err := gen.Generate(); errors.As(err, &commitErr) {
+		fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+	} else if err != nil {
+		return fmt.Errorf("failed to generate README: %w", err)
 	}
 
 	fmt.Printf("generated: %s\n", readmePath)
 	return nil
 }
 
+// checkReadme compares the generated content with README.md on disk
+func checkReadme(readmePath, content string) error {
+	existing, err := os.ReadFile(readmePath)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read README: %w", err)
+	}
+
+	diff := readme.UnifiedDiff(readmePath, readmePath+" (generated)", string(existing), content)
+	if diff == "" {
+		fmt.Printf("up to date: %s\n", readmePath)
+		return nil
+	}
+
+	fmt.Print(diff)
+	return fmt.Errorf("%s is out of date, run 'crumb readme' to regenerate it", readmePath)
+}
+
 // runConfig opens the config file in $EDITOR (or vim if not set)
-func runConfig() error {
+func runConfig(cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("config", flag.ContinueOnError)
+	show := fs.Bool("show", false, "print the effective config (repo .crumb.yaml merged with user config)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *show {
+		return showConfig(cfg)
+	}
+
 	// get config file path
 	configPath, err := xdg.ConfigFile("crumb/config.yaml")
 	if err != nil {
@@ -179,14 +272,8 @@ func runConfig() error {
 		}
 	}
 
-	// get editor from environment, default to vim
-	editor := os.Getenv("EDITOR")
-	if editor == "" {
-		editor = "vim"
-	}
-
-	// open config in editor
-	cmd := exec.Command(editor, configPath)
+	// open config in $EDITOR (default vim)
+	cmd := config.EditorCommand(configPath)
 	cmd.Stdin = os.Stdin
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
@@ -199,43 +286,78 @@ func runConfig() error {
 }
 
 // runInit creates the prompts directory and initial README
-func runInit(cfg *config.Config) error {
-	// get current working directory
-	cwd, err := os.Getwd()
+func runInit(cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("init", flag.ContinueOnError)
+	addCommonFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	promptsDir, err := crumbsDir(cfg)
 	if err != nil {
-		return fmt.Errorf("failed to get current directory: %w", err)
+		return err
 	}
 
 	// create prompts directory
-	promptsDir := filepath.Join(cwd, cfg.OutputDir)
 	if err := os.MkdirAll(promptsDir, 0755); err != nil {
 		return fmt.Errorf("failed to create directory: %w", err)
 	}
 
-	// create initial README.md
+	// create initial README.md, keeping anything outside the index
 	readmePath := filepath.Join(promptsDir, "README.md")
-	initialContent := `# Prompts
+	gen := readme.NewGenerator(promptsDir, readme.Options{
+		Groups:   cfg.ReadmeGroups,
+		Template: cfg.ReadmeTemplate,
+		Roster:   cfg.Roster,
+	})
+	if err := gen.Generate(); err != nil {
+		return fmt.Errorf("failed to generate README: %w", err)
+	}
 
-A shared collection of AI prompts captured by the team. Learn from each other's techniques, discover effective patterns, and build institutional knowledge around AI-assisted development.
+	fmt.Printf("initialized: %s\n", promptsDir)
+	fmt.Printf("created: %s\n", readmePath)
+	return nil
+}
 
-**What is this?** This directory contains prompts saved using [crumb](https://github.com/rsnodgrass/crumb), a tool for capturing and sharing AI prompts across a team.
+// addCommonFlags registers the flags every command accepts, so they work
+// before or after the command name
+func addCommonFlags(fs *flag.FlagSet) {
+	fs.StringVar(&dirFlag, "dir", dirFlag, "crumbs directory (overrides $CRUMB_DIR and output_dir)")
+	fs.BoolVar(&commitFlag, "commit", commitFlag, "git commit saved crumbs (like git.auto_commit in config)")
+}
 
-## Index
+// crumbsDir returns the absolute crumbs directory: --dir, $CRUMB_DIR, or
+// output_dir relative to the git work tree (or working directory)
+func crumbsDir(cfg *config.Config) (string, error) {
+	return config.CrumbsDir(cfg, dirFlag)
+}
 
-| Date | Author | Tool | Tags | Title |
-|------|--------|------|------|-------|
+// newStorage opens the crumbs directory, committing saves when --commit or
+// git.auto_commit is set and mapping authors through the roster
+func newStorage(cfg *config.Config, dir string) (*storage.MarkdownStorage, error) {
+	committer, err := newCommitter(cfg)
+	if err != nil {
+		return nil, err
+	}
+	s := storage.NewMarkdownStorage(dir)
+	s.Git = committer
+	s.Roster = cfg.Roster
+	return s, nil
+}
 
----
-*Run ` + "`crumb readme`" + ` to regenerate this index.*
-`
+// listCrumbs reads every crumb in dir with authors mapped through the roster
+func listCrumbs(cfg *config.Config, dir string) ([]*storage.Crumb, error) {
+	s := storage.NewMarkdownStorage(dir)
+	s.Roster = cfg.Roster
+	return s.List()
+}
 
-	if err := os.WriteFile(readmePath, []byte(initialContent), 0644); err != nil {
-		return fmt.Errorf("failed to write README: %w", err)
+// newCommitter returns nil when auto-commit is off
+func newCommitter(cfg *config.Config) (*storage.GitCommitter, error) {
+	if !commitFlag && !cfg.Git.CommitEnabled() {
+		return nil, nil
 	}
-
-	fmt.Printf("initialized: %s\n", promptsDir)
-	fmt.Printf("created: %s\n", readmePath)
-	return nil
+	return storage.NewGitCommitter(cfg.Git.Message, cfg.Git.Branch)
 }
 
 // renderMarkdown renders a markdown file using glamour
@@ -262,12 +384,36 @@ func renderMarkdown(path string) error {
 	return nil
 }
 
+// showConfig prints the merged config and the files it came from
+func showConfig(cfg *config.Config) error {
+	var data strings.Builder
+	enc := yaml.NewEncoder(&data)
+	enc.SetIndent(2)
+	if err := enc.Encode(cfg); err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+
+	if len(cfg.Sources) == 0 {
+		fmt.Println("# no config files found, using defaults")
+	} else {
+		fmt.Println("# effective config, merged from:")
+		for _, source := range cfg.Sources {
+			fmt.Printf("#   %s\n", source)
+		}
+	}
+	fmt.Print(data.String())
+	return nil
+}
+
 // writeDefaultConfig writes a default config file
 func writeDefaultConfig(path string) error {
 	defaultContent := `# crumb configuration
 
-# default tool to pre-select in the dropdown
-default_tool: Claude Code
+# settings here override the team defaults in a repository's .crumb.yaml;
+# lists are combined with the repository's
+
+# default tool to pre-select in the dropdown (default: Claude Code)
+# default_tool: Claude Code
 
 # custom tools to add to the dropdown (in addition to built-in tools)
 custom_tools: []
@@ -275,8 +421,42 @@ custom_tools: []
 # favorite tags to suggest when tagging prompts
 favorite_tags: []
 
-# output directory for prompts (relative to current working directory)
-output_dir: crumbs
+# output directory for prompts, relative to the git repository root
+# (or the current directory outside one); $CRUMB_DIR and --dir override it
+# output_dir: crumbs
+
+# extra README index sections grouped by tag, tool and/or author
+readme_groups: []
+
+# optional text/template file for the README index (relative to output_dir)
+# readme_template: .templates/readme.tmpl
+
+# extra starter template directories (Ctrl+O in the TUI, crumb -T <name>),
+# besides ~/.config/crumb/templates and <output_dir>/.templates
+template_dirs: []
+
+# author recorded on new crumbs (default: GIT_AUTHOR_NAME/GIT_AUTHOR_EMAIL,
+# then git config user.name/user.email)
+# author:
+#   name: Jane Doe
+#   email: jane@example.com
+
+# map the names and emails teammates capture under to one name each, so
+# list and README grouping by author aren't split (best in .crumb.yaml)
+# roster:
+#   - name: Jane Doe
+#     aliases: [jane, jdoe]
+#     emails: [jane@example.com, jane@home.example]
+
+# git integration: context records the repository remote, branch, commit and
+# changed files in each crumb; auto_commit (or --commit) commits each saved
+# crumb and README update, optionally on a separate branch so your working
+# branch stays clean
+# git:
+#   context: true
+#   auto_commit: true
+#   message: "crumb: {{.Title}}"
+#   branch: crumbs
 `
 
 	return os.WriteFile(path, []byte(defaultContent), 0644)
@@ -292,27 +472,51 @@ USAGE:
 COMMANDS:
   (default)      launch TUI to capture a new prompt
   <file.md>      render markdown file with syntax highlighting
-  readme         generate/update crumbs/README.md
-  config         open config file in $EDITOR
+  add            save a crumb without the TUI (--prompt-file, --output-file, --tag, -)
+  browse         browse, preview, copy, edit and delete crumbs in the TUI
+  copy <crumb>   copy a crumb's prompt to the clipboard (--with-output, --print)
+  use <crumb>    fill in a template crumb's {{variables}} and copy it (--var k=v, --print)
+  edit <crumb>   edit an existing crumb (file path or search query) in the TUI
+  import claude-code [session.jsonl]
+  import aider [.aider.chat.history.md]
+  import openai-messages|anthropic-messages [file.json]
+                 pick conversation turns to save as crumbs (--all, --tag, --limit)
+  export <crumb> print a crumb as API messages JSON (--format openai-messages|anthropic-messages)
+  list           list crumbs (--tag, --tool, --author, --since, --until, --sort)
+  search <query> full-text search (qualifiers: tag:, tool:, author:, title:)
+  readme         generate/update crumbs/README.md (--group, --template, --check)
+  config         open config file in $EDITOR (--show prints the merged config)
   init           create crumbs/ directory with starter README
 
 FLAGS:
-  -t, --tool <name>    override default tool for this session
-  --stay               don't exit after save (capture multiple prompts)
-  -v, --version        show version
-  -h, --help           show help
+  -t, --tool <name>      override default tool for this session
+  -T, --template <name>  start from a starter template (Ctrl+O in the TUI)
+  --stay                 don't exit after save (capture multiple prompts)
+  --dir <path>           crumbs directory (default: output_dir at the git root)
+  --commit               git commit saved crumbs and README updates
+  -v, --version          show version
+  -h, --help             show help
 
 EXAMPLES:
   crumb                    # launch TUI
   crumb -t "ChatGPT"       # launch TUI with tool override
+  crumb -T bugfix          # launch TUI pre-filled from the bugfix template
   crumb file.md            # render markdown file
+  echo "..." | crumb add - --tag x   # capture from stdin
+  crumb add --prompt-file p.txt --output-file o.txt --tool Aider
+  crumb list --tool Cursor --since 14d   # recent Cursor crumbs
+  crumb search flaky tag:testing          # ranked full-text search
+  crumb import claude-code # pick turns from this project's Claude Code sessions
   crumb readme             # regenerate README
+  crumb readme --group tag --group tool   # add grouped sections
+  crumb readme --check     # fail with a diff if README is stale (CI)
   crumb config             # edit config
   crumb init               # initialize prompts directory
 
 CONFIG:
   Config file: ~/.config/crumb/config.yaml
   Run 'crumb config' to edit
+  CRUMB_DIR overrides the crumbs directory, like --dir
 
 `)
 }
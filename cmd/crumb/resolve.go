@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"crumb/internal/config"
+	"crumb/internal/search"
+	"crumb/internal/storage"
+)
+
+// resolveCrumb finds a crumb by file path (absolute, relative to the working
+// directory or to the crumbs directory) or by search query. A query must
+// match exactly one crumb, or one title exactly. The crumb is re-read from
+// its file, so frontmatter keeps its YAML types and original author rather
+// than the index cache's copy.
+func resolveCrumb(cfg *config.Config, arg string) (*storage.Crumb, error) {
+	dir, err := crumbsDir(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, path := range []string{arg, filepath.Join(dir, arg), filepath.Join(dir, arg+".md")} {
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return storage.ReadCrumb(path)
+		}
+	}
+
+	crumbs, err := listCrumbs(cfg, dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list crumbs: %w", err)
+	}
+
+	for _, c := range crumbs {
+		if strings.EqualFold(c.Title, arg) {
+			return storage.ReadCrumb(c.Path)
+		}
+	}
+
+	results := search.NewIndex(crumbs).Search(search.ParseQuery(arg))
+	switch len(results) {
+	case 0:
+		return nil, fmt.Errorf("no crumb matches %q", arg)
+	case 1:
+		return storage.ReadCrumb(results[0].Crumb.Path)
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%q matches %d crumbs, be more specific or pass a file:", arg, len(results))
+	for i, r := range results {
+		if i == 5 {
+			fmt.Fprintf(&sb, "\n  ...")
+			break
+		}
+		fmt.Fprintf(&sb, "\n  %s  %s", relPath(r.Crumb.Path), r.Crumb.Title)
+	}
+	return nil, fmt.Errorf("%s", sb.String())
+}
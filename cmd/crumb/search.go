@@ -0,0 +1,94 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"crumb/internal/config"
+	"crumb/internal/search"
+)
+
+var (
+	searchTitleStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("#cba6f7")).
+				Bold(true)
+
+	searchMetaStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#6c7086"))
+
+	searchMatchStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("#f9e2af")).
+				Bold(true)
+)
+
+// runSearch ranks crumbs against a full-text query and prints snippets
+func runSearch(cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("search", flag.ContinueOnError)
+	limit := fs.Int("limit", 10, "maximum number of results (0 for all)")
+	addCommonFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	query := search.ParseQuery(strings.Join(fs.Args(), " "))
+	if query.Empty() {
+		return fmt.Errorf("usage: crumb search <query> (e.g. flaky test tag:debugging tool:\"Claude Code\")")
+	}
+
+	dir, err := crumbsDir(cfg)
+	if err != nil {
+		return err
+	}
+
+	crumbs, err := listCrumbs(cfg, dir)
+	if err != nil {
+		return fmt.Errorf("failed to list crumbs: %w", err)
+	}
+
+	results := search.NewIndex(crumbs).Search(query)
+	if len(results) == 0 {
+		fmt.Fprintln(os.Stderr, "no matches")
+		return nil
+	}
+	if *limit > 0 && len(results) > *limit {
+		results = results[:*limit]
+	}
+
+	for i, r := range results {
+		c := r.Crumb
+		fmt.Printf("%d. %s\n", i+1, searchTitleStyle.Render(c.Title))
+
+		meta := []string{c.Date.Format("2006-01-02"), c.Author, c.Tool}
+		if len(c.Tags) > 0 {
+			meta = append(meta, strings.Join(c.Tags, ", "))
+		}
+		meta = append(meta, relPath(c.Path))
+		fmt.Printf("   %s\n", searchMetaStyle.Render(strings.Join(meta, " · ")))
+
+		if r.Snippet.Text != "" {
+			fmt.Printf("   %s\n", r.Snippet.Highlight(func(s string) string {
+				return searchMatchStyle.Render(s)
+			}))
+		}
+		fmt.Println()
+	}
+
+	return nil
+}
+
+// relPath shortens path relative to the working directory when possible
+func relPath(path string) string {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return path
+	}
+	if rel, err := filepath.Rel(cwd, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return rel
+	}
+	return path
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"crumb/internal/clipboard"
+	"crumb/internal/config"
+	"crumb/internal/storage"
+	"crumb/internal/tui"
+)
+
+// varFlags collects repeatable --var name=value flags
+type varFlags map[string]string
+
+func (v varFlags) String() string {
+	pairs := make([]string, 0, len(v))
+	for name, value := range v {
+		pairs = append(pairs, name+"="+value)
+	}
+	return strings.Join(pairs, ",")
+}
+
+func (v varFlags) Set(value string) error {
+	name, val, ok := strings.Cut(value, "=")
+	if !ok || strings.TrimSpace(name) == "" {
+		return fmt.Errorf("expected name=value, got %q", value)
+	}
+	v[strings.TrimSpace(name)] = val
+	return nil
+}
+
+// runUse fills in a template crumb's variables and copies or prints the
+// rendered prompt
+func runUse(cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("use", flag.ContinueOnError)
+
+	values := make(varFlags)
+	fs.Var(values, "var", "set a variable as name=value (repeatable)")
+	printOnly := fs.Bool("print", false, "print the rendered prompt instead of copying it")
+	noInput := fs.Bool("no-input", false, "never open the form; fail if a variable has no value")
+	addCommonFlags(fs)
+
+	positional, err := parseInterspersed(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) == 0 {
+		return fmt.Errorf("usage: crumb use <file|query> [--var name=value]... [--print]")
+	}
+
+	crumb, err := resolveCrumb(cfg, strings.Join(positional, " "))
+	if err != nil {
+		return err
+	}
+
+	for name := range values {
+		if !declaresVariable(crumb, name) {
+			fmt.Fprintf(os.Stderr, "warning: %q has no variable %q\n", crumb.Title, name)
+		}
+	}
+
+	// ask for anything not given on the command line when we can
+	if !*noInput && needsInput(crumb, values) && isTerminal(os.Stdin) && isTerminal(os.Stderr) {
+		p := tea.NewProgram(tui.NewVarForm(crumb, values), tea.WithOutput(os.Stderr))
+		final, err := p.Run()
+		if err != nil {
+			return fmt.Errorf("TUI error: %w", err)
+		}
+		form := final.(tui.VarForm)
+		if !form.Submitted() {
+			return fmt.Errorf("cancelled")
+		}
+		values = form.Values()
+	}
+
+	text, err := crumb.Render(values)
+	if err != nil {
+		return fmt.Errorf("%w (use --var name=value)", err)
+	}
+
+	if *printOnly {
+		fmt.Println(text)
+		return nil
+	}
+
+	method, err := clipboard.Copy(text)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "copied %q to %s\n", crumb.Title, method)
+	return nil
+}
+
+// needsInput reports whether any variable wasn't set with --var
+func needsInput(crumb *storage.Crumb, values map[string]string) bool {
+	for _, v := range crumb.TemplateVariables() {
+		if _, ok := values[v.Name]; !ok {
+			return true
+		}
+	}
+	return false
+}
+
+func declaresVariable(crumb *storage.Crumb, name string) bool {
+	for _, v := range crumb.TemplateVariables() {
+		if v.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,62 @@
+package clipboard
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/atotto/clipboard"
+	"github.com/aymanbagabas/go-osc52/v2"
+)
+
+// Method describes how text reached the clipboard
+type Method string
+
+const (
+	Native Method = "clipboard"                   // pbcopy, xclip, wl-copy, Windows API...
+	OSC52  Method = "terminal clipboard (OSC 52)" // escape sequence handled by the terminal
+)
+
+// Copy places text on the clipboard. Native clipboard tools are used when
+// available; over SSH, or when no tool is installed, an OSC 52 escape
+// sequence asks the local terminal to do it instead.
+func Copy(text string) (Method, error) {
+	if !isRemote() && !clipboard.Unsupported {
+		if err := clipboard.WriteAll(text); err == nil {
+			return Native, nil
+		}
+	}
+
+	if err := copyOSC52(text); err != nil {
+		return "", fmt.Errorf("failed to copy to clipboard: %w", err)
+	}
+	return OSC52, nil
+}
+
+// isRemote reports whether we're in an SSH session, where native tools
+// would copy to the remote machine's clipboard
+func isRemote() bool {
+	return os.Getenv("SSH_TTY") != "" || os.Getenv("SSH_CONNECTION") != ""
+}
+
+// copyOSC52 writes the OSC 52 sequence to the controlling terminal,
+// wrapped for tmux or screen when running inside them
+func copyOSC52(text string) error {
+	seq := osc52.New(text)
+	switch {
+	case os.Getenv("TMUX") != "":
+		seq = seq.Tmux()
+	case strings.HasPrefix(os.Getenv("TERM"), "screen"):
+		seq = seq.Screen()
+	}
+
+	var out io.Writer = os.Stderr
+	if tty, err := os.OpenFile("/dev/tty", os.O_WRONLY, 0); err == nil {
+		defer tty.Close()
+		out = tty
+	}
+
+	_, err := seq.WriteTo(out)
+	return err
+}
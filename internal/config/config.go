@@ -3,9 +3,13 @@ package config
 import (
 	"fmt"
 	"os"
+	"os/exec"
+	"strings"
 
 	"github.com/adrg/xdg"
 	"gopkg.in/yaml.v3"
+
+	"crumb/internal/storage"
 )
 
 // Config represents the application configuration
@@ -13,7 +17,63 @@ type Config struct {
 	DefaultTool  string   `yaml:"default_tool"`
 	CustomTools  []string `yaml:"custom_tools"`
 	FavoriteTags []string `yaml:"favorite_tags"`
-	OutputDir    string   `yaml:"output_dir"` // defaults to "crumbs"
+	OutputDir    string   `yaml:"output_dir"`    // defaults to "crumbs"
+	ReadmeGroups []string `yaml:"readme_groups"` // extra README sections: tag, tool, author
+
+	// ReadmeTemplate is an optional text/template file for the README index,
+	// relative to the output directory
+	ReadmeTemplate string `yaml:"readme_template"`
+
+	// TemplateDirs are extra directories of starter templates, searched
+	// after ~/.config/crumb/templates and before <output_dir>/.templates
+	TemplateDirs []string `yaml:"template_dirs"`
+
+	Git GitConfig `yaml:"git"`
+
+	// Author overrides the name and email taken from git
+	Author storage.Identity `yaml:"author,omitempty"`
+
+	// Roster maps the names and emails teammates capture under to one
+	// canonical name each
+	Roster storage.Roster `yaml:"roster,omitempty"`
+
+	// Sources lists the config files that were loaded, repo config first
+	Sources []string `yaml:"-"`
+}
+
+// GitConfig controls committing saved crumbs and README changes. Switches
+// are pointers so a user's explicit false can override the repo config.
+type GitConfig struct {
+	AutoCommit *bool  `yaml:"auto_commit,omitempty"`
+	Message    string `yaml:"message,omitempty"` // text/template with .Title and .Action
+	Branch     string `yaml:"branch,omitempty"`  // commit here instead of the current branch
+
+	// Context records the remote, branch, commit and changed files of the
+	// working directory's repository in each new crumb
+	Context *bool `yaml:"context,omitempty"`
+}
+
+// CommitEnabled reports whether saved crumbs are committed
+func (g GitConfig) CommitEnabled() bool {
+	return g.AutoCommit != nil && *g.AutoCommit
+}
+
+// ContextEnabled reports whether new crumbs record git context
+func (g GitConfig) ContextEnabled() bool {
+	return g.Context != nil && *g.Context
+}
+
+// Identity returns the author for new crumbs: the configured author, with
+// missing fields taken from git, and the name mapped through the roster
+func (c *Config) Identity() storage.Identity {
+	id := c.Author
+	if id.Name == "" || id.Email == "" {
+		git := storage.GitIdentity()
+		id.Name = pick(id.Name, git.Name)
+		id.Email = pick(id.Email, git.Email)
+	}
+	id.Name = c.Roster.Canonical(id.Name, id.Email)
+	return id
 }
 
 // builtInTools is the hardcoded list of built-in tools
@@ -40,26 +100,41 @@ func DefaultConfig() *Config {
 	}
 }
 
-// Load loads configuration from XDG config path, returns defaults if not found
+// Load reads the user config from the XDG config path and merges it over
+// the repository's .crumb.yaml, if any. Returns defaults if neither exists.
 func Load() (*Config, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current directory: %w", err)
+	}
+	return load(cwd)
+}
+
+func load(dir string) (*Config, error) {
 	configPath, err := xdg.ConfigFile("crumb/config.yaml")
 	if err != nil {
 		return nil, fmt.Errorf("failed to get config file path: %w", err)
 	}
 
-	// if config file doesn't exist, return default config
-	if _, err := os.Stat(configPath); os.IsNotExist(err) {
-		return DefaultConfig(), nil
+	user, err := readFile(configPath)
+	if err != nil {
+		return nil, err
 	}
 
-	data, err := os.ReadFile(configPath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read config file: %w", err)
+	var repo *Config
+	repoPath := FindRepoConfig(dir)
+	if repoPath != "" {
+		if repo, err = readFile(repoPath); err != nil {
+			return nil, err
+		}
 	}
 
-	var cfg Config
-	if err := yaml.Unmarshal(data, &cfg); err != nil {
-		return nil, fmt.Errorf("failed to parse config file: %w", err)
+	cfg := merge(repo, user)
+	if repo != nil {
+		cfg.Sources = append(cfg.Sources, repoPath)
+	}
+	if user != nil {
+		cfg.Sources = append(cfg.Sources, configPath)
 	}
 
 	// apply defaults for empty fields
@@ -76,9 +151,44 @@ func Load() (*Config, error) {
 		cfg.FavoriteTags = []string{}
 	}
 
+	return cfg, nil
+}
+
+// readFile parses one config file, returning nil if it doesn't exist
+func readFile(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
 	return &cfg, nil
 }
 
+// Editor returns the user's editor from $EDITOR, defaulting to vim
+func Editor() string {
+	if editor := os.Getenv("EDITOR"); editor != "" {
+		return editor
+	}
+	return "vim"
+}
+
+// EditorCommand builds the command that opens files in the user's editor.
+// $EDITOR may include arguments, e.g. "code --wait".
+func EditorCommand(files ...string) *exec.Cmd {
+	args := strings.Fields(Editor())
+	if len(args) == 0 {
+		args = []string{"vim"}
+	}
+	return exec.Command(args[0], append(args[1:], files...)...)
+}
+
 // GetAllTools returns the combined list of built-in and custom tools
 func GetAllTools(cfg *Config) []string {
 	if cfg == nil {
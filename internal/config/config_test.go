@@ -3,9 +3,12 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/adrg/xdg"
+
+	"crumb/internal/storage"
 )
 
 func TestDefaultConfig(t *testing.T) {
@@ -188,3 +191,167 @@ func TestLoad_PartialConfig(t *testing.T) {
 		t.Errorf("expected 1 custom tool, got %d", len(cfg.CustomTools))
 	}
 }
+
+func TestEditorCommand(t *testing.T) {
+	t.Setenv("EDITOR", "code --wait")
+
+	cmd := EditorCommand("a.md")
+	if cmd.Args[0] != "code" || strings.Join(cmd.Args[1:], " ") != "--wait a.md" {
+		t.Errorf("expected editor arguments to be split, got %v", cmd.Args)
+	}
+
+	t.Setenv("EDITOR", "")
+	if cmd := EditorCommand("a.md"); cmd.Args[0] != "vim" {
+		t.Errorf("expected vim fallback, got %v", cmd.Args)
+	}
+}
+
+func TestLoad_RepoConfig(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	xdg.Reload()
+	t.Cleanup(xdg.Reload)
+
+	userConfig := `default_tool: Cursor
+custom_tools: [My Tool]
+favorite_tags: [personal, go]
+`
+	if err := os.MkdirAll(filepath.Join(xdg.ConfigHome, "crumb"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(xdg.ConfigHome, "crumb", "config.yaml"), []byte(userConfig), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	repo := t.TempDir()
+	sub := filepath.Join(repo, "src", "pkg")
+	if err := os.MkdirAll(filepath.Join(repo, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(sub, 0755); err != nil {
+		t.Fatal(err)
+	}
+	repoConfig := `default_tool: Aider
+custom_tools: [Team GPT]
+favorite_tags: [go, oncall]
+output_dir: docs/crumbs
+`
+	if err := os.WriteFile(filepath.Join(repo, RepoConfigName), []byte(repoConfig), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := load(sub)
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+
+	if cfg.DefaultTool != "Cursor" {
+		t.Errorf("expected user default tool to win, got %q", cfg.DefaultTool)
+	}
+	if cfg.OutputDir != "docs/crumbs" {
+		t.Errorf("expected repo output dir, got %q", cfg.OutputDir)
+	}
+	if strings.Join(cfg.CustomTools, ",") != "Team GPT,My Tool" {
+		t.Errorf("expected combined custom tools, got %v", cfg.CustomTools)
+	}
+	if strings.Join(cfg.FavoriteTags, ",") != "go,oncall,personal" {
+		t.Errorf("expected combined favorite tags, got %v", cfg.FavoriteTags)
+	}
+	if len(cfg.Sources) != 2 || cfg.Sources[0] != filepath.Join(repo, RepoConfigName) {
+		t.Errorf("expected repo and user config sources, got %v", cfg.Sources)
+	}
+}
+
+func TestMerge_GitSwitches(t *testing.T) {
+	on, off := true, false
+	repo := &Config{Git: GitConfig{AutoCommit: &on}}
+
+	if !merge(repo, &Config{}).Git.CommitEnabled() {
+		t.Error("expected the repo's auto_commit when the user leaves it unset")
+	}
+	if merge(repo, &Config{Git: GitConfig{AutoCommit: &off}}).Git.CommitEnabled() {
+		t.Error("expected the user's auto_commit: false to override the repo")
+	}
+	if !merge(nil, &Config{Git: GitConfig{AutoCommit: &on}}).Git.CommitEnabled() {
+		t.Error("expected the user's auto_commit without a repo config")
+	}
+
+	repo.Git.Context = &on
+	if merge(repo, &Config{Git: GitConfig{Context: &off}}).Git.ContextEnabled() {
+		t.Error("expected the user's context: false to override the repo")
+	}
+}
+
+func TestMerge_IgnoresRepoAuthor(t *testing.T) {
+	repo := &Config{Author: storage.Identity{Name: "Team Bot", Email: "bot@example.com"}}
+
+	if got := merge(repo, &Config{}).Author; got != (storage.Identity{}) {
+		t.Errorf("expected the repo author ignored, got %+v", got)
+	}
+	user := storage.Identity{Name: "Jane Doe"}
+	if got := merge(repo, &Config{Author: user}).Author; got != user {
+		t.Errorf("expected only the user's author, got %+v", got)
+	}
+}
+
+func TestFindRepoConfig_StopsAtGitRoot(t *testing.T) {
+	outer := t.TempDir()
+	repo := filepath.Join(outer, "repo")
+	if err := os.MkdirAll(filepath.Join(repo, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	// a config above the repository root belongs to something else
+	if err := os.WriteFile(filepath.Join(outer, RepoConfigName), []byte("output_dir: x\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if path := FindRepoConfig(repo); path != "" {
+		t.Errorf("expected no repo config, got %s", path)
+	}
+}
+
+func TestCrumbsDir(t *testing.T) {
+	repo := t.TempDir()
+	sub := filepath.Join(repo, "src", "pkg")
+	if err := os.MkdirAll(filepath.Join(repo, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(sub, 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(sub)
+	t.Setenv(DirEnv, "")
+
+	cfg := DefaultConfig()
+	dir, err := CrumbsDir(cfg, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dir != filepath.Join(repo, "crumbs") {
+		t.Errorf("expected crumbs at the git root, got %s", dir)
+	}
+
+	t.Setenv(DirEnv, "notes")
+	if dir, _ := CrumbsDir(cfg, ""); dir != filepath.Join(sub, "notes") {
+		t.Errorf("expected $%s relative to the working directory, got %s", DirEnv, dir)
+	}
+
+	if dir, _ := CrumbsDir(cfg, "/elsewhere"); dir != "/elsewhere" {
+		t.Errorf("expected --dir to win, got %s", dir)
+	}
+}
+
+func TestIdentity(t *testing.T) {
+	t.Setenv("GIT_AUTHOR_NAME", "rsnodgrass")
+	t.Setenv("GIT_AUTHOR_EMAIL", "ryan@laptop.local")
+
+	cfg := DefaultConfig()
+	cfg.Roster = storage.Roster{{Name: "Ryan Snodgrass", Aliases: []string{"rsnodgrass"}}}
+	if id := cfg.Identity(); id.Name != "Ryan Snodgrass" || id.Email != "ryan@laptop.local" {
+		t.Errorf("expected git identity mapped through the roster, got %+v", id)
+	}
+
+	cfg.Author = storage.Identity{Email: "ryan@work.com"}
+	if id := cfg.Identity(); id.Email != "ryan@work.com" || id.Name != "Ryan Snodgrass" {
+		t.Errorf("expected configured email to override git, got %+v", id)
+	}
+}
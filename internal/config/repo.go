@@ -0,0 +1,158 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"crumb/internal/storage"
+)
+
+const (
+	// RepoConfigName is the committed, team-wide config file
+	RepoConfigName = ".crumb.yaml"
+
+	// DirEnv overrides the crumbs directory, like the --dir flag
+	DirEnv = "CRUMB_DIR"
+)
+
+// CrumbsDir resolves the absolute crumbs directory. An override (the --dir
+// flag) wins, then $CRUMB_DIR, both relative to the working directory.
+// Otherwise OutputDir is relative to the enclosing git work tree, so
+// running crumb from a subdirectory finds the same crumbs, falling back
+// to the working directory outside a repository.
+func CrumbsDir(cfg *Config, override string) (string, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	for _, dir := range []string{override, os.Getenv(DirEnv)} {
+		if dir != "" {
+			return resolveAgainst(cwd, dir), nil
+		}
+	}
+
+	base := GitRoot(cwd)
+	if base == "" {
+		base = cwd
+	}
+	return resolveAgainst(base, cfg.OutputDir), nil
+}
+
+func resolveAgainst(base, dir string) string {
+	if filepath.IsAbs(dir) {
+		return filepath.Clean(dir)
+	}
+	return filepath.Join(base, dir)
+}
+
+// FindRepoConfig looks for .crumb.yaml from dir up to the enclosing git
+// work tree's root. Outside a git repository only dir itself is checked.
+// Returns "" if there is none.
+func FindRepoConfig(dir string) string {
+	root := GitRoot(dir)
+	if root == "" {
+		root = dir
+	}
+
+	for {
+		path := filepath.Join(dir, RepoConfigName)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path
+		}
+
+		parent := filepath.Dir(dir)
+		if dir == root || parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// GitRoot returns the root of the git work tree containing dir, found by
+// looking for a .git directory (or file, for worktrees and submodules), or
+// "" outside a repository.
+func GitRoot(dir string) string {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+
+	for {
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// merge layers the user config over the repo config: the repo sets team
+// defaults, the user's scalar settings win, and lists are combined.
+func merge(repo, user *Config) *Config {
+	if repo == nil {
+		repo = &Config{}
+	}
+	if user == nil {
+		user = &Config{}
+	}
+
+	return &Config{
+		DefaultTool:    pick(user.DefaultTool, repo.DefaultTool),
+		CustomTools:    union(repo.CustomTools, user.CustomTools),
+		FavoriteTags:   union(repo.FavoriteTags, user.FavoriteTags),
+		OutputDir:      pick(user.OutputDir, repo.OutputDir),
+		ReadmeGroups:   union(repo.ReadmeGroups, user.ReadmeGroups),
+		ReadmeTemplate: pick(user.ReadmeTemplate, repo.ReadmeTemplate),
+		TemplateDirs:   union(repo.TemplateDirs, user.TemplateDirs),
+		Git: GitConfig{
+			AutoCommit: pickBool(user.Git.AutoCommit, repo.Git.AutoCommit),
+			Message:    pick(user.Git.Message, repo.Git.Message),
+			Branch:     pick(user.Git.Branch, repo.Git.Branch),
+			Context:    pickBool(user.Git.Context, repo.Git.Context),
+		},
+		// the author is personal: a committed one would attribute every
+		// teammate's crumbs to the same person
+		Author: user.Author,
+		// personal entries go last so the team's roster wins on conflicts
+		Roster: append(append(storage.Roster{}, repo.Roster...), user.Roster...),
+	}
+}
+
+func pick(user, repo string) string {
+	if user != "" {
+		return user
+	}
+	return repo
+}
+
+// pickBool returns the user's switch when they set it either way
+func pickBool(user, repo *bool) *bool {
+	if user != nil {
+		return user
+	}
+	return repo
+}
+
+// union appends the values of b missing from a, keeping a's order
+func union(a, b []string) []string {
+	if a == nil && b == nil {
+		return nil
+	}
+
+	seen := make(map[string]bool, len(a)+len(b))
+	result := make([]string, 0, len(a)+len(b))
+	for _, list := range [][]string{a, b} {
+		for _, v := range list {
+			if !seen[v] {
+				seen[v] = true
+				result = append(result, v)
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,128 @@
+package importer
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	// AiderTool is the tool recorded on imported Aider turns
+	AiderTool = "Aider"
+
+	// AiderHistoryFile is where Aider logs chats, in the repository root
+	AiderHistoryFile = ".aider.chat.history.md"
+)
+
+// aiderSessionPrefix starts each session's header line
+const aiderSessionPrefix = "# aider chat started at "
+
+// aiderPromptCommands are chat commands whose argument is a prompt
+var aiderPromptCommands = []string{"/ask", "/code", "/architect"}
+
+// ParseAider reads an Aider chat history into one candidate per user
+// message (consecutive "####" lines) with the reply up to the next message
+// as output. Aider's own "> " log lines and other chat commands are left
+// out.
+func ParseAider(r io.Reader) ([]Candidate, error) {
+	var candidates []Candidate
+	var session time.Time
+	var sessionHeader string
+	var prompt, output []string
+	inPrompt := false
+	repeats := make(map[string]int) // same prompt sent again in a session
+
+	flush := func() {
+		text := aiderPrompt(prompt)
+		if text != "" {
+			// the history is append-only, so the session header, prompt and
+			// repeat count identify a turn across imports
+			repeats[text]++
+			sum := sha256.Sum256([]byte(fmt.Sprintf("%s\x00%s\x00%d", sessionHeader, text, repeats[text])))
+			candidates = append(candidates, Candidate{
+				ID:     "aider:" + hex.EncodeToString(sum[:8]),
+				Tool:   AiderTool,
+				Prompt: text,
+				Output: strings.TrimSpace(strings.Join(output, "\n")),
+				Time:   session,
+			})
+		}
+		prompt, output = nil, nil
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, aiderSessionPrefix):
+			flush()
+			inPrompt = false
+			clear(repeats)
+			sessionHeader = line
+			session, _ = time.ParseInLocation("2006-01-02 15:04:05", strings.TrimPrefix(line, aiderSessionPrefix), time.Local)
+		case line == "####" || strings.HasPrefix(line, "#### "):
+			// consecutive "####" lines are one multi-line message
+			if !inPrompt {
+				flush()
+				inPrompt = true
+			}
+			prompt = append(prompt, strings.TrimPrefix(strings.TrimPrefix(line, "####"), " "))
+		case line == ">" || strings.HasPrefix(line, "> "):
+			// aider's own output (commands run, edits applied, commits)
+			inPrompt = false
+		default:
+			inPrompt = false
+			if len(prompt) > 0 {
+				output = append(output, line)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read chat history: %w", err)
+	}
+	flush()
+
+	return candidates, nil
+}
+
+// aiderPrompt joins the message lines, unwrapping /ask, /code and
+// /architect and dropping other chat commands (/add, /run, ...)
+func aiderPrompt(lines []string) string {
+	text := strings.TrimSpace(strings.Join(lines, "\n"))
+	if !strings.HasPrefix(text, "/") {
+		return text
+	}
+	for _, cmd := range aiderPromptCommands {
+		if rest, ok := strings.CutPrefix(text, cmd); ok && (rest == "" || rest[0] == ' ' || rest[0] == '\n') {
+			return strings.TrimSpace(rest)
+		}
+	}
+	return ""
+}
+
+// LoadAider parses an Aider chat history file, returning its turns newest
+// first
+func LoadAider(path string) ([]Candidate, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open chat history: %w", err)
+	}
+	defer f.Close()
+
+	candidates, err := ParseAider(f)
+	if err != nil {
+		return nil, err
+	}
+
+	// sessions are appended, so reversing puts the newest first
+	for i, j := 0, len(candidates)-1; i < j; i, j = i+1, j-1 {
+		candidates[i], candidates[j] = candidates[j], candidates[i]
+	}
+	return candidates, nil
+}
@@ -0,0 +1,83 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+const aiderHistory = `
+# aider chat started at 2024-06-01 10:00:00
+
+> /usr/local/bin/aider --model sonnet
+> Aider v0.60.0
+
+#### /add retry.go
+
+> Added retry.go to the chat
+
+#### why is TestRetry flaky?
+#### it fails about 1 in 20 runs
+
+The sleep races the ticker.
+
+> Tokens: 2.1k sent, 300 received.
+
+#### fix it
+
+retry.go
+<<<<<<< SEARCH
+time.Sleep(d)
+=======
+<-ticker.C
+>>>>>>> REPLACE
+
+> Applied edit to retry.go
+> Commit abc1234 fix: wait on the ticker
+
+# aider chat started at 2024-06-02 09:00:00
+
+#### /ask is the fix safe?
+
+Yes.
+
+#### fix it
+`
+
+func TestParseAider(t *testing.T) {
+	candidates, err := ParseAider(strings.NewReader(aiderHistory))
+	if err != nil {
+		t.Fatalf("ParseAider failed: %v", err)
+	}
+	if len(candidates) != 4 {
+		t.Fatalf("expected 4 prompts, got %d: %+v", len(candidates), candidates)
+	}
+
+	first := candidates[0]
+	if first.Prompt != "why is TestRetry flaky?\nit fails about 1 in 20 runs" || first.Tool != AiderTool {
+		t.Errorf("expected multi-line prompt, got %q", first.Prompt)
+	}
+	if first.Output != "The sleep races the ticker." {
+		t.Errorf("expected reply without aider's log lines, got %q", first.Output)
+	}
+	if want := time.Date(2024, 6, 1, 10, 0, 0, 0, time.Local); !first.Time.Equal(want) {
+		t.Errorf("expected session start time, got %v", first.Time)
+	}
+	if !strings.HasPrefix(candidates[1].Output, "retry.go\n<<<<<<< SEARCH") {
+		t.Errorf("expected the edit as output, got %q", candidates[1].Output)
+	}
+	if candidates[2].Prompt != "is the fix safe?" {
+		t.Errorf("expected /ask to be unwrapped, got %q", candidates[2].Prompt)
+	}
+
+	// the same prompt in another session is a different turn
+	if candidates[1].ID == candidates[3].ID {
+		t.Error("expected distinct IDs for repeated prompts in different sessions")
+	}
+
+	// and parsing again gives the same IDs, so re-imports are skipped
+	again, _ := ParseAider(strings.NewReader(aiderHistory))
+	if again[0].ID != first.ID {
+		t.Error("expected stable IDs")
+	}
+}
@@ -0,0 +1,191 @@
+package importer
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ClaudeCodeTool is the tool recorded on imported Claude Code turns
+const ClaudeCodeTool = "Claude Code"
+
+// claudeCodeLine is the subset of a Claude Code session log entry we read
+type claudeCodeLine struct {
+	Type        string    `json:"type"`
+	UUID        string    `json:"uuid"`
+	Timestamp   time.Time `json:"timestamp"`
+	IsSidechain bool      `json:"isSidechain"` // subagent traffic
+	IsMeta      bool      `json:"isMeta"`      // injected by Claude Code, not typed
+	IsAPIError  bool      `json:"isApiErrorMessage"`
+	Message     struct {
+		Content json.RawMessage `json:"content"`
+	} `json:"message"`
+}
+
+// contentBlock is one block of a message's content array
+type contentBlock struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// text returns the message's text blocks (or plain string content) and
+// whether it had any content besides tool results
+func (l claudeCodeLine) text() (string, bool) {
+	var s string
+	if err := json.Unmarshal(l.Message.Content, &s); err == nil {
+		return s, true
+	}
+
+	var blocks []contentBlock
+	if err := json.Unmarshal(l.Message.Content, &blocks); err != nil {
+		return "", false
+	}
+	var texts []string
+	typed := false
+	for _, b := range blocks {
+		switch b.Type {
+		case "text":
+			texts = append(texts, b.Text)
+			typed = true
+		case "image", "document":
+			typed = true
+		}
+	}
+	return joinText(texts), typed
+}
+
+// isCommandNoise matches slash command bookkeeping Claude Code logs as user
+// messages
+func isCommandNoise(text string) bool {
+	for _, prefix := range []string{"<command-name>", "<command-message>", "<local-command-", "Caveat: "} {
+		if strings.HasPrefix(text, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseClaudeCode reads a Claude Code session log (JSONL) into one
+// candidate per typed user prompt, with the assistant's text replies up to
+// the next prompt as the output. Tool calls, tool results and thinking are
+// left out; lines that aren't valid JSON are skipped.
+func ParseClaudeCode(r io.Reader) ([]Candidate, error) {
+	var candidates []Candidate
+	var output []string
+
+	flush := func() {
+		if n := len(candidates); n > 0 {
+			candidates[n-1].Output = joinText(output)
+		}
+		output = nil
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024) // tool results can be huge
+	for scanner.Scan() {
+		var line claudeCodeLine
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil || line.IsSidechain {
+			continue
+		}
+
+		switch line.Type {
+		case "user":
+			text, typed := line.text()
+			text = strings.TrimSpace(text)
+			if !typed || line.IsMeta || text == "" || isCommandNoise(text) {
+				continue
+			}
+			flush()
+			candidates = append(candidates, Candidate{
+				ID:     "claude-code:" + line.UUID,
+				Tool:   ClaudeCodeTool,
+				Prompt: text,
+				Time:   line.Timestamp,
+			})
+		case "assistant":
+			if line.IsAPIError || len(candidates) == 0 {
+				continue
+			}
+			if text, _ := line.text(); text != "" {
+				output = append(output, text)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read session: %w", err)
+	}
+	flush()
+
+	return candidates, nil
+}
+
+// ClaudeCodeProjectsDir returns where Claude Code keeps session logs:
+// $CLAUDE_CONFIG_DIR/projects, or ~/.claude/projects
+func ClaudeCodeProjectsDir() (string, error) {
+	if dir := os.Getenv("CLAUDE_CONFIG_DIR"); dir != "" {
+		return filepath.Join(dir, "projects"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find home directory: %w", err)
+	}
+	return filepath.Join(home, ".claude", "projects"), nil
+}
+
+var nonAlphanumeric = regexp.MustCompile(`[^a-zA-Z0-9]`)
+
+// ClaudeCodeSessions lists the session logs Claude Code recorded for a
+// project directory, most recently modified first
+func ClaudeCodeSessions(projectsDir, projectDir string) ([]string, error) {
+	// Claude Code names each project's folder after its path with every
+	// non-alphanumeric character replaced by "-"
+	dir := filepath.Join(projectsDir, nonAlphanumeric.ReplaceAllString(projectDir, "-"))
+	paths, err := filepath.Glob(filepath.Join(dir, "*.jsonl"))
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no Claude Code sessions for %s in %s (pass a session .jsonl file instead)", projectDir, projectsDir)
+	}
+
+	modTimes := make(map[string]time.Time, len(paths))
+	for _, path := range paths {
+		if info, err := os.Stat(path); err == nil {
+			modTimes[path] = info.ModTime()
+		}
+	}
+	sort.SliceStable(paths, func(i, j int) bool {
+		return modTimes[paths[i]].After(modTimes[paths[j]])
+	})
+	return paths, nil
+}
+
+// LoadClaudeCode parses session log files, returning their turns newest
+// first
+func LoadClaudeCode(paths ...string) ([]Candidate, error) {
+	var all []Candidate
+	for _, path := range paths {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open session: %w", err)
+		}
+		candidates, err := ParseClaudeCode(f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		all = append(all, candidates...)
+	}
+
+	sort.SliceStable(all, func(i, j int) bool {
+		return all[i].Time.After(all[j].Time)
+	})
+	return all, nil
+}
@@ -0,0 +1,88 @@
+package importer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"crumb/internal/storage"
+)
+
+const claudeCodeSession = `{"type":"queue-operation","operation":"enqueue"}
+{"type":"user","uuid":"u1","timestamp":"2024-06-01T10:00:00Z","message":{"role":"user","content":"Why is TestRetry flaky?"}}
+{"type":"assistant","uuid":"a1","message":{"role":"assistant","content":[{"type":"thinking","thinking":"hmm"},{"type":"text","text":"Let me look."},{"type":"tool_use","name":"Read","input":{}}]}}
+{"type":"user","uuid":"t1","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"x","content":"file contents"}]}}
+{"type":"assistant","uuid":"s1","isSidechain":true,"message":{"role":"assistant","content":[{"type":"text","text":"subagent chatter"}]}}
+{"type":"assistant","uuid":"a2","message":{"role":"assistant","content":[{"type":"text","text":"The sleep races the ticker."}]}}
+{"type":"user","uuid":"m1","isMeta":true,"message":{"role":"user","content":[{"type":"text","text":"Continue from where you left off."}]}}
+{"type":"user","uuid":"c1","message":{"role":"user","content":"<command-name>/clear</command-name>"}}
+not json
+{"type":"user","uuid":"u2","timestamp":"2024-06-01T10:05:00Z","message":{"role":"user","content":[{"type":"text","text":"Now fix it"}]}}
+{"type":"assistant","uuid":"e1","isApiErrorMessage":true,"message":{"role":"assistant","content":[{"type":"text","text":"API Error: overloaded"}]}}
+`
+
+func TestParseClaudeCode(t *testing.T) {
+	candidates, err := ParseClaudeCode(strings.NewReader(claudeCodeSession))
+	if err != nil {
+		t.Fatalf("ParseClaudeCode failed: %v", err)
+	}
+	if len(candidates) != 2 {
+		t.Fatalf("expected 2 typed prompts, got %d: %+v", len(candidates), candidates)
+	}
+
+	first := candidates[0]
+	if first.ID != "claude-code:u1" || first.Tool != ClaudeCodeTool || first.Prompt != "Why is TestRetry flaky?" {
+		t.Errorf("unexpected first candidate: %+v", first)
+	}
+	if first.Output != "Let me look.\n\nThe sleep races the ticker." {
+		t.Errorf("expected assistant text only, got %q", first.Output)
+	}
+	if !first.Time.Equal(time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected prompt timestamp, got %v", first.Time)
+	}
+	if candidates[1].Prompt != "Now fix it" || candidates[1].Output != "" {
+		t.Errorf("unexpected second candidate: %+v", candidates[1])
+	}
+}
+
+func TestClaudeCodeSessions(t *testing.T) {
+	projects := t.TempDir()
+	dir := filepath.Join(projects, "-home-me-src-my-app")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for i, name := range []string{"old.jsonl", "new.jsonl"} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(claudeCodeSession), 0644); err != nil {
+			t.Fatal(err)
+		}
+		mtime := time.Now().Add(time.Duration(i-2) * time.Hour)
+		if err := os.Chtimes(path, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	sessions, err := ClaudeCodeSessions(projects, "/home/me/src/my_app")
+	if err != nil {
+		t.Fatalf("ClaudeCodeSessions failed: %v", err)
+	}
+	if len(sessions) != 2 || filepath.Base(sessions[0]) != "new.jsonl" {
+		t.Errorf("expected newest session first, got %v", sessions)
+	}
+
+	if _, err := ClaudeCodeSessions(projects, "/elsewhere"); err == nil {
+		t.Error("expected error for a project without sessions")
+	}
+}
+
+func TestSkipImported(t *testing.T) {
+	candidates := []Candidate{{ID: "claude-code:u1"}, {ID: "claude-code:u2"}}
+	crumbs := []*storage.Crumb{{Title: "done", ImportID: "claude-code:u1"}}
+
+	fresh := SkipImported(candidates, crumbs)
+	if len(fresh) != 1 || fresh[0].ID != "claude-code:u2" {
+		t.Errorf("expected only the unimported turn, got %+v", fresh)
+	}
+}
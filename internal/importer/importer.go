@@ -0,0 +1,65 @@
+// Package importer turns conversations logged by AI tools into crumbs.
+package importer
+
+import (
+	"strings"
+	"time"
+
+	"crumb/internal/storage"
+)
+
+// Candidate is one prompt and its response, ready to be saved as a crumb
+type Candidate struct {
+	// ID identifies the turn across imports, e.g. "claude-code:<uuid>"
+	ID     string
+	Tool   string
+	System string
+	Prompt string
+	Output string
+	Time   time.Time // when the prompt was sent, zero if unknown
+}
+
+// Crumb builds the crumb for a candidate, dated when the prompt was sent
+func (c Candidate) Crumb(tags []string, author storage.Identity) *storage.Crumb {
+	crumb := storage.NewCrumb(c.Prompt, c.Output, "", c.Tool, tags, author)
+	if !c.Time.IsZero() {
+		crumb.Date = c.Time.Local()
+	}
+	crumb.System = c.System
+	crumb.ImportID = c.ID
+	return crumb
+}
+
+// Title is a one-line summary of the prompt for pickers
+func (c Candidate) Title() string {
+	return storage.GenerateTitle(c.Prompt)
+}
+
+// SkipImported drops candidates already imported into crumbs, keeping order
+func SkipImported(candidates []Candidate, crumbs []*storage.Crumb) []Candidate {
+	imported := make(map[string]bool, len(crumbs))
+	for _, c := range crumbs {
+		if c.ImportID != "" {
+			imported[c.ImportID] = true
+		}
+	}
+
+	result := make([]Candidate, 0, len(candidates))
+	for _, c := range candidates {
+		if !imported[c.ID] {
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+// joinText joins non-empty chunks with blank lines
+func joinText(chunks []string) string {
+	var kept []string
+	for _, chunk := range chunks {
+		if chunk = strings.TrimSpace(chunk); chunk != "" {
+			kept = append(kept, chunk)
+		}
+	}
+	return strings.Join(kept, "\n\n")
+}
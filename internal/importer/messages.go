@@ -0,0 +1,58 @@
+package importer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"crumb/internal/messages"
+)
+
+// Tools recorded on crumbs imported from messages JSON
+const (
+	OpenAITool    = "OpenAI API"
+	AnthropicTool = "Anthropic API"
+)
+
+// ParseMessages turns an OpenAI or Anthropic messages document into one
+// candidate per user message, with the assistant replies up to the next
+// user message as output and the system prompt on every candidate.
+func ParseMessages(data []byte, tool string) ([]Candidate, error) {
+	conv, err := messages.Parse(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []Candidate
+	var output []string
+	flush := func() {
+		if n := len(candidates); n > 0 {
+			candidates[n-1].Output = joinText(output)
+			candidates[n-1].ID = messagesID(candidates[n-1])
+		}
+		output = nil
+	}
+
+	for _, m := range conv.Messages {
+		if m.Role == "assistant" {
+			output = append(output, m.Content)
+			continue
+		}
+		flush()
+		candidates = append(candidates, Candidate{
+			Tool:   tool,
+			System: conv.System,
+			Prompt: strings.TrimSpace(m.Content),
+		})
+	}
+	flush()
+
+	return candidates, nil
+}
+
+// messagesID identifies a turn by its content, since messages JSON has no
+// IDs of its own
+func messagesID(c Candidate) string {
+	sum := sha256.Sum256([]byte(c.System + "\x00" + c.Prompt + "\x00" + c.Output))
+	return "messages:" + hex.EncodeToString(sum[:8])
+}
@@ -0,0 +1,27 @@
+package importer
+
+import "testing"
+
+func TestParseMessagesMultiTurn(t *testing.T) {
+	data := `{"system":"Be brief.","messages":[
+		{"role":"user","content":"Why is it flaky?"},
+		{"role":"assistant","content":"A race."},
+		{"role":"user","content":"Fix it"},
+		{"role":"assistant","content":"Done."},
+		{"role":"assistant","content":"Also added a test."}]}`
+
+	candidates, err := ParseMessages([]byte(data), AnthropicTool)
+	if err != nil {
+		t.Fatalf("ParseMessages failed: %v", err)
+	}
+	if len(candidates) != 2 {
+		t.Fatalf("expected a candidate per user turn, got %d", len(candidates))
+	}
+	second := candidates[1]
+	if second.Prompt != "Fix it" || second.Output != "Done.\n\nAlso added a test." || second.System != "Be brief." {
+		t.Errorf("unexpected second candidate: %+v", second)
+	}
+	if candidates[0].ID == "" || candidates[0].ID == second.ID {
+		t.Error("expected distinct content IDs")
+	}
+}
@@ -0,0 +1,162 @@
+// Package messages converts crumbs to and from the chat message JSON used
+// by the OpenAI and Anthropic APIs and their playgrounds.
+package messages
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"crumb/internal/storage"
+)
+
+// supported formats
+const (
+	// OpenAI is {"messages": [...]} with the system prompt as a "system"
+	// message
+	OpenAI = "openai-messages"
+
+	// Anthropic is {"system": "...", "messages": [...]}
+	Anthropic = "anthropic-messages"
+)
+
+// Formats lists the supported formats
+var Formats = []string{OpenAI, Anthropic}
+
+// Message is one chat message with plain text content
+type Message struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// Conversation is a parsed messages document
+type Conversation struct {
+	System   string
+	Messages []Message // user and assistant turns, in order
+}
+
+// Marshal encodes a crumb as a messages document: its system prompt, the
+// prompt as the user message and, when withOutput is set, the output as
+// the assistant's reply.
+func Marshal(c *storage.Crumb, format string, withOutput bool) ([]byte, error) {
+	msgs := []Message{{Role: "user", Content: c.Prompt}}
+	if withOutput && strings.TrimSpace(c.Output) != "" {
+		msgs = append(msgs, Message{Role: "assistant", Content: c.Output})
+	}
+
+	var doc any
+	switch format {
+	case OpenAI:
+		if c.System != "" {
+			msgs = append([]Message{{Role: "system", Content: c.System}}, msgs...)
+		}
+		doc = struct {
+			Messages []Message `json:"messages"`
+		}{msgs}
+	case Anthropic:
+		doc = struct {
+			System   string    `json:"system,omitempty"`
+			Messages []Message `json:"messages"`
+		}{c.System, msgs}
+	default:
+		return nil, fmt.Errorf("unknown format: %s (use %s)", format, strings.Join(Formats, " or "))
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return nil, fmt.Errorf("failed to encode messages: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// rawMessage accepts both string content and an array of content parts
+type rawMessage struct {
+	Role    string          `json:"role"`
+	Content json.RawMessage `json:"content"`
+}
+
+// contentPart is a text part ({"type": "text", "text": ...}); other part
+// types (images, tool calls) are skipped
+type contentPart struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// Parse reads either format, or a bare messages array. "system" and
+// "developer" messages and a top-level "system" (a string or text blocks)
+// become the system prompt; messages without text are dropped.
+func Parse(data []byte) (*Conversation, error) {
+	var doc struct {
+		System   json.RawMessage `json:"system"`
+		Messages []rawMessage    `json:"messages"`
+	}
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		if err := json.Unmarshal(trimmed, &doc.Messages); err != nil {
+			return nil, fmt.Errorf("failed to parse messages: %w", err)
+		}
+	} else if err := json.Unmarshal(trimmed, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse messages: %w", err)
+	}
+	if len(doc.Messages) == 0 {
+		return nil, fmt.Errorf("no messages found")
+	}
+
+	conv := &Conversation{}
+	var system []string
+	if len(doc.System) > 0 {
+		text, err := contentText(doc.System)
+		if err != nil {
+			return nil, fmt.Errorf("invalid system prompt: %w", err)
+		}
+		system = append(system, text)
+	}
+
+	for i, m := range doc.Messages {
+		text, err := contentText(m.Content)
+		if err != nil {
+			return nil, fmt.Errorf("message %d: %w", i+1, err)
+		}
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+		switch m.Role {
+		case "system", "developer":
+			system = append(system, text)
+		case "user", "assistant":
+			conv.Messages = append(conv.Messages, Message{Role: m.Role, Content: text})
+		default:
+			// tool results and the like aren't part of a prompt
+		}
+	}
+	conv.System = strings.TrimSpace(strings.Join(system, "\n\n"))
+	return conv, nil
+}
+
+// contentText flattens string or content-part content to text
+func contentText(raw json.RawMessage) (string, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return "", nil
+	}
+
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s, nil
+	}
+
+	var parts []contentPart
+	if err := json.Unmarshal(raw, &parts); err != nil {
+		return "", fmt.Errorf("content must be a string or an array of parts")
+	}
+	var texts []string
+	for _, p := range parts {
+		if p.Type == "text" || p.Type == "input_text" || p.Type == "output_text" {
+			texts = append(texts, p.Text)
+		}
+	}
+	return strings.Join(texts, "\n\n"), nil
+}
@@ -0,0 +1,78 @@
+package messages
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"crumb/internal/storage"
+)
+
+func TestMarshal(t *testing.T) {
+	c := &storage.Crumb{System: "Be brief.", Prompt: "Why <nil>?", Output: "Because."}
+
+	data, err := Marshal(c, OpenAI, true)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	var openai struct{ Messages []Message }
+	if err := json.Unmarshal(data, &openai); err != nil {
+		t.Fatal(err)
+	}
+	roles := make([]string, len(openai.Messages))
+	for i, m := range openai.Messages {
+		roles[i] = m.Role
+	}
+	if strings.Join(roles, ",") != "system,user,assistant" {
+		t.Errorf("expected system, user and assistant messages, got %v", roles)
+	}
+	if !strings.Contains(string(data), "Why <nil>?") {
+		t.Errorf("expected HTML characters left unescaped:\n%s", data)
+	}
+
+	data, err = Marshal(c, Anthropic, false)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	var anthropic struct {
+		System   string
+		Messages []Message
+	}
+	if err := json.Unmarshal(data, &anthropic); err != nil {
+		t.Fatal(err)
+	}
+	if anthropic.System != "Be brief." || len(anthropic.Messages) != 1 || anthropic.Messages[0].Role != "user" {
+		t.Errorf("expected top-level system and only the user message, got %+v", anthropic)
+	}
+
+	if _, err := Marshal(c, "xml", true); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		system string
+		turns  int
+	}{
+		{"bare array", `[{"role":"user","content":"hi"},{"role":"assistant","content":"hello"}]`, "", 2},
+		{"openai developer", `{"messages":[{"role":"developer","content":"Be brief."},{"role":"user","content":"hi"}]}`, "Be brief.", 1},
+		{"anthropic blocks", `{"system":[{"type":"text","text":"Be brief."}],"messages":[{"role":"user","content":[{"type":"text","text":"hi"},{"type":"image","source":{}}]}]}`, "Be brief.", 1},
+	}
+	for _, tt := range tests {
+		conv, err := Parse([]byte(tt.input))
+		if err != nil {
+			t.Errorf("%s: Parse failed: %v", tt.name, err)
+			continue
+		}
+		if conv.System != tt.system || len(conv.Messages) != tt.turns {
+			t.Errorf("%s: expected system %q and %d messages, got %+v", tt.name, tt.system, tt.turns, conv)
+		}
+	}
+
+	if _, err := Parse([]byte(`{"model":"x"}`)); err == nil {
+		t.Error("expected error without messages")
+	}
+}
@@ -0,0 +1,56 @@
+package readme
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	if diff := UnifiedDiff("a", "b", "same\n", "same\n"); diff != "" {
+		t.Errorf("expected no diff for identical input, got %q", diff)
+	}
+
+	a := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n"
+	b := "1\n2\nthree\n4\n5\n6\n7\n8\n9\n10\n11\n12\n13\n"
+
+	expected := `--- old
++++ new
+@@ -1,6 +1,6 @@
+ 1
+ 2
+-3
++three
+ 4
+ 5
+ 6
+@@ -10,3 +10,4 @@
+ 10
+ 11
+ 12
++13
+`
+	if diff := UnifiedDiff("old", "new", a, b); diff != expected {
+		t.Errorf("unexpected diff:\n%s\nexpected:\n%s", diff, expected)
+	}
+}
+
+func TestUnifiedDiffFromEmpty(t *testing.T) {
+	diff := UnifiedDiff("old", "new", "", "x\ny\n")
+	if !strings.Contains(diff, "@@ -0,0 +1,2 @@\n+x\n+y\n") {
+		t.Errorf("unexpected diff for new file:\n%s", diff)
+	}
+}
+
+func TestUnifiedDiffMissingNewline(t *testing.T) {
+	expected := `--- old
++++ new
+@@ -1,2 +1,2 @@
+ x
+-y
+\ No newline at end of file
++y
+`
+	if diff := UnifiedDiff("old", "new", "x\ny", "x\ny\n"); diff != expected {
+		t.Errorf("unexpected diff:\n%s\nexpected:\n%s", diff, expected)
+	}
+}
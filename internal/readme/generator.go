@@ -4,97 +4,382 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+
+	"crumb/internal/storage"
+)
+
+// Group names accepted in Options.Groups
+const (
+	GroupTag    = "tag"
+	GroupTool   = "tool"
+	GroupAuthor = "author"
+)
+
+// header is the intro written above the index for new READMEs
+const header = `# Prompts
+
+A shared collection of AI prompts captured by the team. Learn from each other's techniques, discover effective patterns, and build institutional knowledge around AI-assisted development.
+
+**What is this?** This directory contains prompts saved using [crumb](https://github.com/rsnodgrass/crumb), a tool for capturing and sharing AI prompts across a team.
+
+`
+
+// footer closes the generated README
+const footer = "---\n*Run `crumb readme` to regenerate this index.*\n"
+
+// Markers delimit the generated index; everything outside them is left
+// untouched when the README is regenerated.
+const (
+	StartMarker = "<!-- crumb:index:start -->"
+	EndMarker   = "<!-- crumb:index:end -->"
 )
 
-type Prompt struct {
-	Title       string
-	Description string
-	Tags        []string
-	Filename    string
+// legacyHeadings start the index section in READMEs written before markers
+var legacyHeadings = []string{"## Index", "## Available Prompts"}
+
+// Options controls the optional sections of the generated index
+type Options struct {
+	Groups []string // any of GroupTag, GroupTool, GroupAuthor, in output order
+
+	// Template is a text/template file that replaces the built-in index
+	// layout. Relative paths are resolved against the prompts directory.
+	Template string
+
+	// Git commits the regenerated README when set
+	Git *storage.GitCommitter
+
+	// Roster merges author aliases before grouping
+	Roster storage.Roster
 }
 
 type Generator struct {
 	promptsDir string
+	opts       Options
 }
 
-func NewGenerator(promptsDir string) *Generator {
+func NewGenerator(promptsDir string, opts Options) *Generator {
 	return &Generator{
 		promptsDir: promptsDir,
+		opts:       opts,
 	}
 }
 
 func (g *Generator) Generate() error {
-	prompts, err := g.scanPrompts()
+	// hold the directory lock so a crumb saved meanwhile isn't missed
+	lock, err := storage.LockDir(g.promptsDir)
 	if err != nil {
-		return fmt.Errorf("failed to scan prompts: %w", err)
+		return err
 	}
+	defer lock.Unlock()
 
-	readmeContent := g.formatReadme(prompts)
-	readmePath := filepath.Join(g.promptsDir, "README.md")
+	content, err := g.Content()
+	if err != nil {
+		return err
+	}
 
-	if err := os.WriteFile(readmePath, []byte(readmeContent), 0644); err != nil {
+	readmePath := filepath.Join(g.promptsDir, "README.md")
+	if err := os.WriteFile(readmePath, []byte(content), 0644); err != nil {
 		return fmt.Errorf("failed to write README: %w", err)
 	}
 
-	return nil
+	return g.opts.Git.Commit(storage.CommitData{Title: "update README index", Action: "readme"}, readmePath)
 }
 
-func (g *Generator) scanPrompts() ([]Prompt, error) {
-	entries, err := os.ReadDir(g.promptsDir)
+// Content renders the README without writing it. An existing README keeps
+// everything outside the generated region.
+func (g *Generator) Content() (string, error) {
+	if err := ValidateGroups(g.opts.Groups); err != nil {
+		return "", err
+	}
+
+	s := storage.NewMarkdownStorage(g.promptsDir)
+	s.Roster = g.opts.Roster
+	crumbs, err := s.List()
+	if err != nil {
+		return "", fmt.Errorf("failed to scan prompts: %w", err)
+	}
+
+	// newest first
+	if err := storage.SortCrumbs(crumbs, "date", false); err != nil {
+		return "", err
+	}
+
+	index := formatIndex(crumbs, g.opts)
+	if g.opts.Template != "" {
+		index, err = renderTemplate(g.templatePath(), crumbs)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	existing, err := os.ReadFile(filepath.Join(g.promptsDir, "README.md"))
 	if err != nil {
 		if os.IsNotExist(err) {
-			return []Prompt{}, nil
+			return g.formatReadme(index), nil
+		}
+		return "", fmt.Errorf("failed to read README: %w", err)
+	}
+
+	return Merge(string(existing), index)
+}
+
+// templatePath resolves the configured template against the prompts directory
+func (g *Generator) templatePath() string {
+	path := g.opts.Template
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, rest)
 		}
-		return nil, err
 	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(g.promptsDir, path)
+	}
+	return path
+}
+
+func (g *Generator) formatReadme(index string) string {
+	var sb strings.Builder
 
-	prompts := make([]Prompt, 0)
-	for _, entry := range entries {
-		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".md") || entry.Name() == "README.md" {
+	sb.WriteString(header)
+	sb.WriteString(wrapIndex(index))
+	sb.WriteString("\n")
+	sb.WriteString(footer)
+
+	return sb.String()
+}
+
+// wrapIndex surrounds the generated index with the region markers
+func wrapIndex(index string) string {
+	return StartMarker + "\n" + index + EndMarker + "\n"
+}
+
+// Merge replaces the generated region of an existing README with index.
+// READMEs without markers get them around their legacy index section, or
+// appended at the end if there is none. A README with only one marker, or
+// with them out of order, is an error rather than gaining a second pair.
+func Merge(existing, index string) (string, error) {
+	start := strings.Index(existing, StartMarker)
+	end := strings.Index(existing, EndMarker)
+	if start >= 0 && end > start {
+		return existing[:start] + wrapIndex(index) + strings.TrimPrefix(existing[end+len(EndMarker):], "\n"), nil
+	}
+	if start >= 0 || end >= 0 {
+		return "", fmt.Errorf("README has mismatched %s and %s markers; fix or remove them and run 'crumb readme' again", StartMarker, EndMarker)
+	}
+
+	lines := strings.SplitAfter(existing, "\n")
+	for i, line := range lines {
+		if !isLegacyHeading(line) {
 			continue
 		}
 
-		prompt := Prompt{
-			Filename: entry.Name(),
-			Title:    strings.TrimSuffix(entry.Name(), ".md"),
+		// the legacy section runs to the footer rule or the next heading
+		j := i + 1
+		for j < len(lines) {
+			trimmed := strings.TrimSpace(lines[j])
+			if trimmed == "---" || strings.HasPrefix(trimmed, "## ") {
+				break
+			}
+			j++
 		}
-		prompts = append(prompts, prompt)
+
+		rest := strings.Join(lines[j:], "")
+		if rest != "" {
+			rest = "\n" + rest
+		}
+		return strings.Join(lines[:i], "") + wrapIndex(index) + rest, nil
+	}
+
+	if existing != "" && !strings.HasSuffix(existing, "\n") {
+		existing += "\n"
 	}
+	return existing + "\n" + wrapIndex(index), nil
+}
 
-	return prompts, nil
+func isLegacyHeading(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	for _, h := range legacyHeadings {
+		if trimmed == h {
+			return true
+		}
+	}
+	return false
 }
 
-func (g *Generator) formatReadme(prompts []Prompt) string {
+// formatIndex renders the summary, the full table and any grouped sections
+func formatIndex(crumbs []*storage.Crumb, opts Options) string {
 	var sb strings.Builder
 
-	sb.WriteString("# Prompt Library\n\n")
-	sb.WriteString("Collection of prompts for various tasks.\n\n")
+	sb.WriteString("## Index\n\n")
 
-	if len(prompts) == 0 {
-		sb.WriteString("No prompts available yet.\n")
-		return sb.String()
+	if len(crumbs) > 0 {
+		sb.WriteString(summary(crumbs))
+		sb.WriteString("\n\n")
 	}
 
-	sb.WriteString("## Available Prompts\n\n")
+	sb.WriteString("| Date | Author | Tool | Tags | Title |\n")
+	sb.WriteString("|------|--------|------|------|-------|\n")
+	for _, c := range crumbs {
+		sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s |\n",
+			c.Date.Format("2006-01-02"),
+			escapeCell(c.Author),
+			escapeCell(c.Tool),
+			escapeCell(strings.Join(c.Tags, ", ")),
+			link(c)+commitSuffix(c),
+		))
+	}
+
+	if len(crumbs) == 0 {
+		sb.WriteString("\n_No crumbs yet. Run `crumb` to capture one._\n")
+	}
 
-	for _, prompt := range prompts {
-		sb.WriteString("- [")
-		sb.WriteString(prompt.Title)
-		sb.WriteString("](")
-		sb.WriteString(prompt.Filename)
-		sb.WriteString(")\n")
+	for _, group := range opts.Groups {
+		if len(crumbs) == 0 {
+			break
+		}
+		sb.WriteString("\n")
+		sb.WriteString(formatGroup(crumbs, group))
 	}
 
 	return sb.String()
 }
 
-// Generate is a convenience function that creates a generator and generates the README
-func Generate(promptsDir string) (string, error) {
-	g := NewGenerator(promptsDir)
-	prompts, err := g.scanPrompts()
-	if err != nil {
-		return "", fmt.Errorf("failed to scan prompts: %w", err)
+// summary returns a one-line count of crumbs, authors and tools
+func summary(crumbs []*storage.Crumb) string {
+	authors := len(groupBy(crumbs, GroupAuthor))
+	tools := len(groupBy(crumbs, GroupTool))
+	return fmt.Sprintf("**%s** from %s using %s.",
+		plural(len(crumbs), "crumb"),
+		plural(authors, "author"),
+		plural(tools, "tool"),
+	)
+}
+
+// formatGroup renders a "By Tag"/"By Tool"/"By Author" section with counts
+func formatGroup(crumbs []*storage.Crumb, group string) string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("## By %s\n", strings.ToUpper(group[:1])+group[1:]))
+
+	for _, g := range groupBy(crumbs, group) {
+		sb.WriteString(fmt.Sprintf("\n### %s (%d)\n\n", g.Name, len(g.Crumbs)))
+		for _, c := range g.Crumbs {
+			sb.WriteString(fmt.Sprintf("- %s — %s, %s\n", link(c), c.Date.Format("2006-01-02"), c.Author))
+		}
+	}
+
+	return sb.String()
+}
+
+// Group is a named set of crumbs sharing a tag, tool or author
+type Group struct {
+	Name   string
+	Crumbs []*storage.Crumb
+}
+
+// groupBy buckets crumbs by field, largest group first. Crumbs keep their
+// incoming order within a group; crumbs without tags land in "untagged".
+func groupBy(crumbs []*storage.Crumb, field string) []Group {
+	index := make(map[string]int)
+	var groups []Group
+
+	add := func(name string, c *storage.Crumb) {
+		i, ok := index[name]
+		if !ok {
+			i = len(groups)
+			index[name] = i
+			groups = append(groups, Group{Name: name})
+		}
+		groups[i].Crumbs = append(groups[i].Crumbs, c)
+	}
+
+	for _, c := range crumbs {
+		switch field {
+		case GroupTag:
+			if len(c.Tags) == 0 {
+				add("untagged", c)
+			}
+			for _, tag := range c.Tags {
+				add(tag, c)
+			}
+		case GroupTool:
+			add(orUnknown(c.Tool), c)
+		case GroupAuthor:
+			add(orUnknown(c.Author), c)
+		}
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		if len(groups[i].Crumbs) != len(groups[j].Crumbs) {
+			return len(groups[i].Crumbs) > len(groups[j].Crumbs)
+		}
+		return strings.ToLower(groups[i].Name) < strings.ToLower(groups[j].Name)
+	})
+	return groups
+}
+
+// ValidateGroups rejects unknown group names
+func ValidateGroups(groups []string) error {
+	for _, g := range groups {
+		switch g {
+		case GroupTag, GroupTool, GroupAuthor:
+		default:
+			return fmt.Errorf("unknown readme group: %s (use tag, tool or author)", g)
+		}
+	}
+	return nil
+}
+
+// link renders a markdown link to the crumb file
+func link(c *storage.Crumb) string {
+	return fmt.Sprintf("[%s](%s)", escapeCell(escapeLinkText(c.Title)), filepath.Base(c.Path))
+}
+
+// commitLink links to the commit a crumb was captured on, or shows the
+// short SHA when the remote has no known web URL
+func commitLink(c *storage.Crumb) string {
+	if c.Git == nil || c.Git.Commit == "" {
+		return ""
+	}
+	if url := c.Git.CommitURL(); url != "" {
+		return fmt.Sprintf("[`%s`](%s)", c.Git.ShortCommit(), url)
+	}
+	return fmt.Sprintf("`%s`", c.Git.ShortCommit())
+}
+
+// commitSuffix appends the commit link to a title cell
+func commitSuffix(c *storage.Crumb) string {
+	if l := commitLink(c); l != "" {
+		return " @ " + l
 	}
-	return g.formatReadme(prompts), nil
+	return ""
+}
+
+func escapeCell(s string) string {
+	return strings.ReplaceAll(s, "|", `\|`)
+}
+
+func escapeLinkText(s string) string {
+	return strings.NewReplacer("[", `\[`, "]", `\]`).Replace(s)
+}
+
+func orUnknown(s string) string {
+	if strings.TrimSpace(s) == "" {
+		return "Unknown"
+	}
+	return s
+}
+
+func plural(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("1 %s", noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+// Generate is a convenience function that creates a generator and generates the README
+func Generate(promptsDir string, opts Options) (string, error) {
+	return NewGenerator(promptsDir, opts).Content()
 }
@@ -0,0 +1,229 @@
+package readme
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/adrg/xdg"
+
+	"crumb/internal/storage"
+)
+
+// writeCrumbs saves crumbs into a temporary directory and returns it
+func writeCrumbs(t *testing.T, crumbs ...*storage.Crumb) string {
+	t.Helper()
+
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	xdg.Reload()
+	t.Cleanup(xdg.Reload)
+
+	dir := t.TempDir()
+	s := storage.NewMarkdownStorage(dir)
+	for _, c := range crumbs {
+		if _, err := s.SaveCrumb(c); err != nil {
+			t.Fatalf("SaveCrumb failed: %v", err)
+		}
+	}
+	return dir
+}
+
+func TestGenerateTable(t *testing.T) {
+	dir := writeCrumbs(t,
+		&storage.Crumb{
+			Title:  "Older | piped",
+			Date:   time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			Author: "Ryan",
+			Tool:   "Cursor",
+			Tags:   []string{"go"},
+			Prompt: "p",
+		},
+		&storage.Crumb{
+			Title:  "Newer",
+			Date:   time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC),
+			Author: "Alex",
+			Tool:   "Aider",
+			Prompt: "p",
+		},
+	)
+
+	content, err := Generate(dir, Options{})
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	newer := strings.Index(content, "| 2024-06-01 | Alex | Aider |  | [Newer](2024-06-01-newer.md) |")
+	older := strings.Index(content, `| 2024-01-01 | Ryan | Cursor | go | [Older \| piped](2024-01-01-older-piped.md) |`)
+	if newer < 0 || older < 0 {
+		t.Fatalf("missing table rows:\n%s", content)
+	}
+	if newer > older {
+		t.Error("expected newest crumb first")
+	}
+	if !strings.Contains(content, "**2 crumbs** from 2 authors using 2 tools.") {
+		t.Errorf("missing summary line:\n%s", content)
+	}
+	if strings.Contains(content, "## By Tag") {
+		t.Error("grouped sections should be opt-in")
+	}
+}
+
+func TestGenerateGroups(t *testing.T) {
+	dir := writeCrumbs(t,
+		&storage.Crumb{Title: "One", Date: time.Now(), Author: "Ryan", Tool: "Cursor", Tags: []string{"go", "testing"}, Prompt: "p"},
+		&storage.Crumb{Title: "Two", Date: time.Now(), Author: "Ryan", Tool: "Cursor", Tags: []string{"go"}, Prompt: "p"},
+	)
+
+	content, err := Generate(dir, Options{Groups: []string{GroupTag, GroupAuthor}})
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	for _, want := range []string{"## By Tag", "### go (2)", "### testing (1)", "## By Author", "### Ryan (2)"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("missing %q in:\n%s", want, content)
+		}
+	}
+	if strings.Index(content, "### go (2)") > strings.Index(content, "### testing (1)") {
+		t.Error("expected larger groups first")
+	}
+
+	if _, err := Generate(dir, Options{Groups: []string{"month"}}); err == nil {
+		t.Error("expected error for unknown group")
+	}
+}
+
+func TestMergePreservesHandWrittenContent(t *testing.T) {
+	existing := "# Our prompts\n\nCustom intro.\n\n" + StartMarker + "\nold index\n" + EndMarker + "\n\nCustom outro.\n"
+
+	merged, err := Merge(existing, "new index\n")
+	if err != nil {
+		t.Fatalf("Merge failed: %v", err)
+	}
+	expected := "# Our prompts\n\nCustom intro.\n\n" + StartMarker + "\nnew index\n" + EndMarker + "\n\nCustom outro.\n"
+	if merged != expected {
+		t.Errorf("unexpected merge:\n%s\nexpected:\n%s", merged, expected)
+	}
+
+	// merging again must be stable
+	if again, _ := Merge(merged, "new index\n"); again != merged {
+		t.Errorf("merge is not idempotent:\n%s", again)
+	}
+}
+
+func TestMergeLegacyReadme(t *testing.T) {
+	legacy := "# Prompts\n\nIntro.\n\n## Index\n\n| Date | Author | Tool | Tags | Title |\n|------|--------|------|------|-------|\n\n---\n*Run `crumb readme` to regenerate this index.*\n"
+
+	merged, err := Merge(legacy, "## Index\n\nrows\n")
+	if err != nil {
+		t.Fatalf("Merge failed: %v", err)
+	}
+	expected := "# Prompts\n\nIntro.\n\n" + StartMarker + "\n## Index\n\nrows\n" + EndMarker + "\n\n---\n*Run `crumb readme` to regenerate this index.*\n"
+	if merged != expected {
+		t.Errorf("unexpected legacy merge:\n%s\nexpected:\n%s", merged, expected)
+	}
+
+	// READMEs without any index section get the region appended
+	merged, _ = Merge("# Notes", "rows\n")
+	if merged != "# Notes\n\n"+StartMarker+"\nrows\n"+EndMarker+"\n" {
+		t.Errorf("unexpected appended merge:\n%q", merged)
+	}
+}
+
+func TestMergeMismatchedMarkers(t *testing.T) {
+	for _, existing := range []string{
+		"# Prompts\n\n" + StartMarker + "\nold index\n",
+		"# Prompts\n\n" + EndMarker + "\nold index\n" + StartMarker + "\n",
+	} {
+		if merged, err := Merge(existing, "rows\n"); err == nil {
+			t.Errorf("expected an error for mismatched markers, got:\n%s", merged)
+		}
+	}
+}
+
+func TestGenerateWithTemplate(t *testing.T) {
+	dir := writeCrumbs(t,
+		&storage.Crumb{Title: "A very long title that gets cut", Date: time.Now(), Author: "Ryan", Tool: "Cursor", Tags: []string{"go"}, Prompt: "p"},
+		&storage.Crumb{Title: "Beta", Date: time.Now().AddDate(0, 0, -3), Author: "Alex", Tool: "Aider", Tags: []string{"go"}, Prompt: "p"},
+	)
+
+	tmpl := `{{range groupBy "tag" .Crumbs}}## {{.Name}}
+{{range sortBy "title" .Crumbs}}* {{truncate 10 .Title}} ({{relativeDate .Date}}) -> {{filename .}}
+{{end}}{{end}}`
+	if err := os.WriteFile(filepath.Join(dir, "layout.tmpl"), []byte(tmpl), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := Generate(dir, Options{Template: "layout.tmpl"})
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	expected := StartMarker + "\n## go\n" +
+		"* A very lo… (today) -> " + filenameFor(t, dir, "A very long") + "\n" +
+		"* Beta (3 days ago) -> " + filenameFor(t, dir, "Beta") + "\n" +
+		EndMarker
+	if !strings.Contains(content, expected) {
+		t.Errorf("template output missing, got:\n%s\nexpected to contain:\n%s", content, expected)
+	}
+
+	if _, err := Generate(dir, Options{Template: "missing.tmpl"}); err == nil {
+		t.Error("expected error for missing template")
+	}
+}
+
+// filenameFor finds the saved file whose name contains the slug of title
+func filenameFor(t *testing.T, dir, title string) string {
+	t.Helper()
+	entries, _ := os.ReadDir(dir)
+	for _, e := range entries {
+		if strings.Contains(e.Name(), storage.Slugify(title)) {
+			return e.Name()
+		}
+	}
+	t.Fatalf("no file for %q", title)
+	return ""
+}
+
+func TestGenerateLinksCommit(t *testing.T) {
+	dir := writeCrumbs(t, &storage.Crumb{
+		Title:  "With context",
+		Date:   time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC),
+		Author: "Alex",
+		Tool:   "Aider",
+		Prompt: "p",
+		Git: &storage.GitContext{
+			Remote: "git@github.com:acme/api.git",
+			Commit: "3f9c2a1d0e5b",
+		},
+	})
+
+	content, err := Generate(dir, Options{})
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	want := "[With context](2024-06-01-with-context.md) @ [`3f9c2a1`](https://github.com/acme/api/commit/3f9c2a1d0e5b) |"
+	if !strings.Contains(content, want) {
+		t.Errorf("expected commit link in the index:\n%s", content)
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		n       int
+		s, want string
+	}{
+		{10, "short", "short"},
+		{6, "a longer title", "a lon…"},
+		{1, "abc", "a"},
+		{0, "abc", ""},
+		{-3, "abc", ""},
+	}
+	for _, tt := range tests {
+		if got := truncate(tt.n, tt.s); got != tt.want {
+			t.Errorf("truncate(%d, %q): expected %q, got %q", tt.n, tt.s, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package readme
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"crumb/internal/storage"
+)
+
+// TemplateData is passed to user-supplied README templates
+type TemplateData struct {
+	Crumbs    []*storage.Crumb // newest first
+	Tags      []Group
+	Tools     []Group
+	Authors   []Group
+	Generated time.Time // differs every run, so --check can't pass with it
+}
+
+// templateFuncs are available to README templates
+func templateFuncs(now time.Time) template.FuncMap {
+	return template.FuncMap{
+		// groupBy "tag" .Crumbs
+		"groupBy": func(field string, crumbs []*storage.Crumb) ([]Group, error) {
+			if err := ValidateGroups([]string{field}); err != nil {
+				return nil, err
+			}
+			return groupBy(crumbs, field), nil
+		},
+		// sortBy "title" .Crumbs, prefix the field with "-" to reverse
+		"sortBy": func(field string, crumbs []*storage.Crumb) ([]*storage.Crumb, error) {
+			sorted := append([]*storage.Crumb(nil), crumbs...)
+			reverse := strings.HasPrefix(field, "-")
+			err := storage.SortCrumbs(sorted, strings.TrimPrefix(field, "-"), reverse)
+			return sorted, err
+		},
+		"truncate": truncate,
+		// like .Generated, output depends on when the index is generated
+		"relativeDate": func(t time.Time) string {
+			return relativeDate(t, now)
+		},
+		"filename": func(c *storage.Crumb) string { return filepath.Base(c.Path) },
+		"link":     link,
+		"commit":   commitLink,
+		"escape":   escapeCell,
+		"join":     strings.Join,
+	}
+}
+
+// renderTemplate executes the template file at path over the crumbs
+func renderTemplate(path string, crumbs []*storage.Crumb) (string, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read README template: %w", err)
+	}
+
+	now := time.Now()
+	tmpl, err := template.New(filepath.Base(path)).Funcs(templateFuncs(now)).Parse(string(src))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse README template: %w", err)
+	}
+
+	data := TemplateData{
+		Crumbs:    crumbs,
+		Tags:      groupBy(crumbs, GroupTag),
+		Tools:     groupBy(crumbs, GroupTool),
+		Authors:   groupBy(crumbs, GroupAuthor),
+		Generated: now,
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to render README template: %w", err)
+	}
+
+	out := buf.String()
+	if out != "" && !strings.HasSuffix(out, "\n") {
+		out += "\n"
+	}
+	return out, nil
+}
+
+// truncate shortens s to at most n runes, adding an ellipsis when cut
+func truncate(n int, s string) string {
+	n = max(n, 0) // templates can pass anything
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	if n <= 1 {
+		return string(runes[:n])
+	}
+	return strings.TrimSpace(string(runes[:n-1])) + "…"
+}
+
+// relativeDate describes t relative to now, e.g. "3 days ago"
+func relativeDate(t, now time.Time) string {
+	days := int(now.Sub(t).Hours() / 24)
+
+	switch {
+	case days < 0:
+		return t.Format("2006-01-02")
+	case days == 0:
+		return "today"
+	case days == 1:
+		return "yesterday"
+	case days < 14:
+		return fmt.Sprintf("%d days ago", days)
+	case days < 60:
+		return fmt.Sprintf("%d weeks ago", days/7)
+	case days < 365:
+		return fmt.Sprintf("%d months ago", days/30)
+	default:
+		return plural(days/365, "year") + " ago"
+	}
+}
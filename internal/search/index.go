@@ -0,0 +1,179 @@
+package search
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"crumb/internal/storage"
+)
+
+// BM25 tuning parameters
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// field weights: a match in the title or tags says more than one buried in
+// a long pasted output
+var fieldWeights = [numFields]float64{
+	fieldTitle:  3.0,
+	fieldTags:   2.0,
+	fieldPrompt: 1.0,
+	fieldOutput: 0.5,
+}
+
+const (
+	fieldTitle = iota
+	fieldTags
+	fieldPrompt
+	fieldOutput
+	numFields
+)
+
+type document struct {
+	crumb  *storage.Crumb
+	terms  [numFields]map[string]int
+	length float64 // weighted token count
+	text   string  // lowercased title + tags + prompt + output, for phrases
+}
+
+// Index is an in-memory BM25 index over a set of crumbs.
+type Index struct {
+	docs      []*document
+	docFreq   map[string]int
+	avgLength float64
+}
+
+// Result is a ranked search hit.
+type Result struct {
+	Crumb   *storage.Crumb
+	Score   float64
+	Snippet Snippet
+}
+
+// NewIndex tokenizes the crumbs and builds the term statistics.
+func NewIndex(crumbs []*storage.Crumb) *Index {
+	idx := &Index{docFreq: make(map[string]int)}
+
+	var total float64
+	for _, c := range crumbs {
+		doc := &document{crumb: c}
+		fields := [numFields]string{
+			fieldTitle:  c.Title,
+			fieldTags:   strings.Join(c.Tags, " "),
+			fieldPrompt: c.Prompt,
+			fieldOutput: c.Output,
+		}
+
+		seen := make(map[string]bool)
+		for f, text := range fields {
+			doc.terms[f] = make(map[string]int)
+			tokens := Tokenize(text)
+			for _, tok := range tokens {
+				doc.terms[f][tok]++
+				if !seen[tok] {
+					seen[tok] = true
+					idx.docFreq[tok]++
+				}
+			}
+			doc.length += fieldWeights[f] * float64(len(tokens))
+		}
+		doc.text = strings.ToLower(strings.Join(fields[:], "\n"))
+
+		total += doc.length
+		idx.docs = append(idx.docs, doc)
+	}
+
+	if len(idx.docs) > 0 {
+		idx.avgLength = total / float64(len(idx.docs))
+	}
+	return idx
+}
+
+// Search returns crumbs matching q, best first. Queries with only
+// qualifiers return every matching crumb, newest first.
+func (idx *Index) Search(q Query) []Result {
+	filter := storage.Filter{Tags: q.Tags, Tool: q.Tool, Author: q.Author}
+	terms := uniqueTerms(q.Terms, q.TitleTerms)
+
+	var results []Result
+	for _, doc := range idx.docs {
+		if !filter.Match(doc.crumb) || !doc.matchesRequired(q) {
+			continue
+		}
+
+		score := idx.score(doc, terms)
+		if len(terms) > 0 && score == 0 {
+			continue
+		}
+
+		results = append(results, Result{
+			Crumb:   doc.crumb,
+			Score:   score,
+			Snippet: makeSnippet(doc.crumb, terms),
+		})
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Crumb.Date.After(results[j].Crumb.Date)
+	})
+	return results
+}
+
+// score computes a BM25 score using field-weighted term frequencies
+func (idx *Index) score(doc *document, terms []string) float64 {
+	n := float64(len(idx.docs))
+	norm := 1.0
+	if idx.avgLength > 0 {
+		norm = 1 - bm25B + bm25B*doc.length/idx.avgLength
+	}
+
+	var score float64
+	for _, term := range terms {
+		var tf float64
+		for f := 0; f < numFields; f++ {
+			tf += fieldWeights[f] * float64(doc.terms[f][term])
+		}
+		if tf == 0 {
+			continue
+		}
+
+		df := float64(idx.docFreq[term])
+		idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+		score += idf * tf * (bm25K1 + 1) / (tf + bm25K1*norm)
+	}
+	return score
+}
+
+// matchesRequired checks quoted phrases and title: qualifiers
+func (doc *document) matchesRequired(q Query) bool {
+	for _, phrase := range q.Phrases {
+		if !strings.Contains(doc.text, phrase) {
+			return false
+		}
+	}
+	for _, term := range q.TitleTerms {
+		if doc.terms[fieldTitle][term] == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func uniqueTerms(lists ...[]string) []string {
+	seen := make(map[string]bool)
+	var result []string
+	for _, terms := range lists {
+		for _, t := range terms {
+			if !seen[t] {
+				seen[t] = true
+				result = append(result, t)
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,127 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Query is a parsed search string. Free text is ranked, qualifiers filter.
+//
+//	fix flaky "race condition" tag:testing tool:"Claude Code" author:ryan title:ci
+type Query struct {
+	Terms      []string // free-text terms, tokenized
+	Phrases    []string // quoted phrases that must appear verbatim
+	TitleTerms []string // terms that must appear in the title
+	Tags       []string
+	Tool       string
+	Author     string
+}
+
+// Empty reports whether the query has no terms and no qualifiers.
+func (q Query) Empty() bool {
+	return len(q.Terms) == 0 && len(q.Phrases) == 0 && len(q.TitleTerms) == 0 &&
+		len(q.Tags) == 0 && q.Tool == "" && q.Author == ""
+}
+
+// ParseQuery splits a raw search string into terms, phrases and qualifiers.
+func ParseQuery(raw string) Query {
+	var q Query
+
+	for _, word := range splitQuery(raw) {
+		key, value, hasKey := strings.Cut(word.text, ":")
+		if hasKey && !word.quoted && value != "" {
+			switch strings.ToLower(key) {
+			case "tag", "tags":
+				q.Tags = append(q.Tags, unquote(value))
+				continue
+			case "tool":
+				q.Tool = unquote(value)
+				continue
+			case "author":
+				q.Author = unquote(value)
+				continue
+			case "title":
+				q.TitleTerms = append(q.TitleTerms, Tokenize(unquote(value))...)
+				continue
+			}
+		}
+
+		if word.quoted {
+			phrase := strings.TrimSpace(word.text)
+			if phrase != "" {
+				q.Phrases = append(q.Phrases, strings.ToLower(phrase))
+			}
+		}
+		q.Terms = append(q.Terms, Tokenize(word.text)...)
+	}
+
+	return q
+}
+
+type queryWord struct {
+	text   string
+	quoted bool
+}
+
+// splitQuery splits on whitespace, keeping "quoted strings" (including
+// qualifier values like tool:"Claude Code") together.
+func splitQuery(raw string) []queryWord {
+	var words []queryWord
+	var current strings.Builder
+	inQuote := false
+	quotedWhole := false
+
+	flush := func() {
+		if current.Len() > 0 {
+			words = append(words, queryWord{text: current.String(), quoted: quotedWhole})
+		}
+		current.Reset()
+		quotedWhole = false
+	}
+
+	for _, r := range raw {
+		switch {
+		case r == '"':
+			if !inQuote && current.Len() == 0 {
+				quotedWhole = true
+			} else if !quotedWhole {
+				current.WriteRune(r)
+			}
+			inQuote = !inQuote
+		case unicode.IsSpace(r) && !inQuote:
+			flush()
+		default:
+			current.WriteRune(r)
+		}
+	}
+	flush()
+
+	return words
+}
+
+func unquote(s string) string {
+	return strings.Trim(s, `"`)
+}
+
+// stopwords are too common to be useful for ranking
+var stopwords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true,
+	"be": true, "by": true, "for": true, "from": true, "in": true, "is": true,
+	"it": true, "of": true, "on": true, "or": true, "that": true, "the": true,
+	"this": true, "to": true, "with": true,
+}
+
+// Tokenize lowercases s and splits it into words, dropping stopwords.
+func Tokenize(s string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	tokens := fields[:0]
+	for _, f := range fields {
+		if !stopwords[f] {
+			tokens = append(tokens, f)
+		}
+	}
+	return tokens
+}
@@ -0,0 +1,103 @@
+package search
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"crumb/internal/storage"
+)
+
+func TestParseQuery(t *testing.T) {
+	q := ParseQuery(`flaky "race condition" tag:testing tool:"Claude Code" author:ryan title:CI`)
+
+	if !reflect.DeepEqual(q.Terms, []string{"flaky", "race", "condition"}) {
+		t.Errorf("unexpected terms %v", q.Terms)
+	}
+	if !reflect.DeepEqual(q.Phrases, []string{"race condition"}) {
+		t.Errorf("unexpected phrases %v", q.Phrases)
+	}
+	if !reflect.DeepEqual(q.Tags, []string{"testing"}) {
+		t.Errorf("unexpected tags %v", q.Tags)
+	}
+	if q.Tool != "Claude Code" {
+		t.Errorf("expected tool 'Claude Code', got %q", q.Tool)
+	}
+	if q.Author != "ryan" {
+		t.Errorf("expected author 'ryan', got %q", q.Author)
+	}
+	if !reflect.DeepEqual(q.TitleTerms, []string{"ci"}) {
+		t.Errorf("unexpected title terms %v", q.TitleTerms)
+	}
+}
+
+func TestSearchRanking(t *testing.T) {
+	now := time.Now()
+	crumbs := []*storage.Crumb{
+		{
+			Title:  "Refactor config loading",
+			Date:   now,
+			Tool:   "Cursor",
+			Prompt: "Split the config loader into smaller functions.",
+		},
+		{
+			Title:  "Debug goroutine deadlock",
+			Date:   now.Add(-time.Hour),
+			Tool:   "Claude Code",
+			Tags:   []string{"debugging"},
+			Prompt: "My program hangs. Find the deadlock between the two goroutines.",
+			Output: "The mutex is locked twice.",
+		},
+		{
+			Title:  "Write changelog",
+			Date:   now.Add(-2 * time.Hour),
+			Tool:   "Claude Code",
+			Prompt: "Summarize commits. Mention the deadlock fix.",
+		},
+	}
+
+	idx := NewIndex(crumbs)
+
+	results := idx.Search(ParseQuery("deadlock"))
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+	if results[0].Crumb.Title != "Debug goroutine deadlock" {
+		t.Errorf("expected title match to rank first, got %q", results[0].Crumb.Title)
+	}
+
+	results = idx.Search(ParseQuery(`deadlock tag:debugging`))
+	if len(results) != 1 {
+		t.Fatalf("expected tag filter to leave 1 result, got %d", len(results))
+	}
+
+	results = idx.Search(ParseQuery(`tool:cursor`))
+	if len(results) != 1 || results[0].Crumb.Tool != "Cursor" {
+		t.Errorf("expected qualifier-only query to filter by tool, got %d results", len(results))
+	}
+
+	results = idx.Search(ParseQuery(`"mutex is locked"`))
+	if len(results) != 1 {
+		t.Errorf("expected phrase to match output body, got %d results", len(results))
+	}
+}
+
+func TestSnippetHighlight(t *testing.T) {
+	c := &storage.Crumb{
+		Prompt: strings.Repeat("filler words here ", 20) + "the Deadlock appears when   both goroutines wait",
+	}
+
+	s := makeSnippet(c, []string{"deadlock", "goroutines"})
+	if s.Field != "prompt" {
+		t.Errorf("expected prompt snippet, got %q", s.Field)
+	}
+
+	marked := s.Highlight(func(m string) string { return "[" + m + "]" })
+	if !strings.Contains(marked, "[Deadlock]") || !strings.Contains(marked, "[goroutines]") {
+		t.Errorf("expected highlighted terms, got %q", marked)
+	}
+	if !strings.HasPrefix(marked, "…") {
+		t.Errorf("expected leading ellipsis for truncated snippet, got %q", marked)
+	}
+}
@@ -0,0 +1,162 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"crumb/internal/storage"
+)
+
+const (
+	snippetWidth   = 160
+	snippetContext = 40
+)
+
+// Snippet is a single-line excerpt of a crumb with matched terms marked.
+type Snippet struct {
+	Field      string   // "prompt" or "output"
+	Text       string   // whitespace-collapsed excerpt
+	Highlights [][2]int // byte ranges of matched terms within Text
+}
+
+// Highlight returns the snippet text with every match wrapped by mark.
+func (s Snippet) Highlight(mark func(string) string) string {
+	var b strings.Builder
+	last := 0
+	for _, h := range s.Highlights {
+		b.WriteString(s.Text[last:h[0]])
+		b.WriteString(mark(s.Text[h[0]:h[1]]))
+		last = h[1]
+	}
+	b.WriteString(s.Text[last:])
+	return b.String()
+}
+
+type wordSpan struct {
+	start, end int
+	word       string
+}
+
+// makeSnippet picks the window of the prompt or output containing the most
+// distinct query terms. Without terms it falls back to the prompt opening.
+func makeSnippet(c *storage.Crumb, terms []string) Snippet {
+	want := make(map[string]bool, len(terms))
+	for _, t := range terms {
+		want[t] = true
+	}
+
+	best := Snippet{Field: "prompt"}
+	bestCount := -1
+
+	for _, field := range []struct {
+		name string
+		text string
+	}{
+		{"prompt", c.Prompt},
+		{"output", c.Output},
+	} {
+		text := strings.Join(strings.Fields(field.text), " ")
+		if text == "" {
+			continue
+		}
+
+		spans := wordSpans(text)
+		start, count := bestWindow(spans, want)
+		if count > bestCount {
+			bestCount = count
+			best = buildSnippet(field.name, text, spans, start, want)
+		}
+		if count == len(want) {
+			break // prompt already has everything, prefer it
+		}
+	}
+
+	return best
+}
+
+// wordSpans returns byte offsets of each word in text
+func wordSpans(text string) []wordSpan {
+	var spans []wordSpan
+	start := -1
+	for i, r := range text {
+		isWord := unicode.IsLetter(r) || unicode.IsDigit(r)
+		if isWord && start < 0 {
+			start = i
+		} else if !isWord && start >= 0 {
+			spans = append(spans, wordSpan{start, i, strings.ToLower(text[start:i])})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		spans = append(spans, wordSpan{start, len(text), strings.ToLower(text[start:])})
+	}
+	return spans
+}
+
+// bestWindow returns the byte offset where the snippet window covering the
+// most distinct wanted terms begins, and how many terms it covers.
+func bestWindow(spans []wordSpan, want map[string]bool) (int, int) {
+	bestStart, bestCount := 0, 0
+	for i, s := range spans {
+		if !want[s.word] {
+			continue
+		}
+
+		seen := make(map[string]bool)
+		for _, other := range spans[i:] {
+			if other.end-s.start > snippetWidth-snippetContext {
+				break
+			}
+			if want[other.word] {
+				seen[other.word] = true
+			}
+		}
+		if len(seen) > bestCount {
+			bestStart, bestCount = s.start, len(seen)
+		}
+	}
+	return bestStart, bestCount
+}
+
+func buildSnippet(field, text string, spans []wordSpan, hit int, want map[string]bool) Snippet {
+	start := 0
+	if hit > snippetContext {
+		start = hit - snippetContext
+		// move forward to a word boundary
+		if i := strings.IndexByte(text[start:hit], ' '); i >= 0 {
+			start += i + 1
+		}
+	}
+	for start < len(text) && !utf8.RuneStart(text[start]) {
+		start++
+	}
+
+	end := len(text)
+	if end-start > snippetWidth {
+		end = start + snippetWidth
+		if i := strings.LastIndexByte(text[start:end], ' '); i > 0 {
+			end = start + i
+		}
+		for end > start && !utf8.RuneStart(text[end]) {
+			end--
+		}
+	}
+
+	prefix, suffix := "", ""
+	if start > 0 {
+		prefix = "…"
+	}
+	if end < len(text) {
+		suffix = "…"
+	}
+
+	s := Snippet{Field: field, Text: prefix + text[start:end] + suffix}
+	for _, span := range spans {
+		if span.start >= start && span.end <= end && want[span.word] {
+			offset := len(prefix) - start
+			s.Highlights = append(s.Highlights, [2]int{span.start + offset, span.end + offset})
+		}
+	}
+	return s
+}
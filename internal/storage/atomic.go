@@ -0,0 +1,123 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+)
+
+// maxFilenameSuffix bounds the search for a free name-N.md
+const maxFilenameSuffix = 1000
+
+// writeFileAtomic writes data to a temp file in the same directory and
+// renames it over path, so readers never see a partially written file.
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp, err := writeTemp(filepath.Dir(path), filepath.Base(path), data, perm)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp)
+	return os.Rename(tmp, path)
+}
+
+// writeFileExclusive writes data to a free name in dir, trying filename,
+// then name-2.md, name-3.md and so on. The complete temp file is linked
+// into place, which fails if the name exists, so the file appears whole or
+// not at all and an existing file is never replaced. On filesystems
+// without hard links (FAT, some network mounts) the file is created with
+// O_EXCL and written in place instead. Returns the path.
+func writeFileExclusive(dir, filename string, data []byte, perm os.FileMode) (string, error) {
+	tmp, err := writeTemp(dir, filename, data, perm)
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp)
+
+	ext := filepath.Ext(filename)
+	base := strings.TrimSuffix(filename, ext)
+	linked := true
+	for n := 1; n <= maxFilenameSuffix; n++ {
+		name := filename
+		if n > 1 {
+			name = fmt.Sprintf("%s-%d%s", base, n, ext)
+		}
+
+		path := filepath.Join(dir, name)
+		if linked {
+			err = os.Link(tmp, path)
+			if linkUnsupported(err) {
+				linked = false
+			}
+		}
+		if !linked {
+			err = writeFileExcl(path, data, perm)
+		}
+		if err == nil {
+			return path, nil
+		}
+		if !os.IsExist(err) {
+			return "", fmt.Errorf("failed to create file: %w", err)
+		}
+	}
+
+	return "", fmt.Errorf("failed to find a free filename for %s", filename)
+}
+
+// linkUnsupported reports whether a hard link failed because the
+// filesystem can't make one, rather than because the name exists
+func linkUnsupported(err error) bool {
+	return errors.Is(err, syscall.EPERM) || errors.Is(err, syscall.ENOTSUP) ||
+		errors.Is(err, syscall.EXDEV) || errors.Is(err, errors.ErrUnsupported)
+}
+
+// writeFileExcl creates path, failing if it exists, and writes data to it,
+// removing the file again if the write fails
+func writeFileExcl(path string, data []byte, perm os.FileMode) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	if err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(path)
+	}
+	return err
+}
+
+// writeTemp writes and syncs data to a new hidden temp file in dir,
+// returning its path
+func writeTemp(dir, filename string, data []byte, perm os.FileMode) (string, error) {
+	tmp, err := os.CreateTemp(dir, "."+filename+"-*.tmp")
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return "", err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return "", err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return "", err
+	}
+	if err := os.Chmod(tmp.Name(), perm); err != nil {
+		os.Remove(tmp.Name())
+		return "", err
+	}
+	return tmp.Name(), nil
+}
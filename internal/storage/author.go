@@ -0,0 +1,93 @@
+package storage
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Identity is who a crumb is attributed to
+type Identity struct {
+	Name  string `yaml:"name,omitempty"`
+	Email string `yaml:"email,omitempty"`
+}
+
+// GitIdentity returns the author git would record for a commit:
+// GIT_AUTHOR_NAME and GIT_AUTHOR_EMAIL, then `git config user.name` and
+// `user.email`. Fields are empty when git doesn't know them either.
+func GitIdentity() Identity {
+	return Identity{
+		Name:  gitIdentityField("GIT_AUTHOR_NAME", "user.name"),
+		Email: gitIdentityField("GIT_AUTHOR_EMAIL", "user.email"),
+	}
+}
+
+func gitIdentityField(env, key string) string {
+	if value := strings.TrimSpace(os.Getenv(env)); value != "" {
+		return value
+	}
+	output, err := exec.Command("git", "config", key).Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// Person is a roster entry mapping the names and emails someone captures
+// under (different machines, nicknames) to one canonical name
+type Person struct {
+	Name    string   `yaml:"name"`
+	Aliases []string `yaml:"aliases,omitempty"`
+	Emails  []string `yaml:"emails,omitempty"`
+}
+
+// Roster maps author aliases to canonical names, so listing and grouping by
+// author isn't split across spellings
+type Roster []Person
+
+// Canonical returns the roster name for an author, matching the email
+// first and then the name or an alias, case-insensitively. Authors missing
+// from the roster are returned unchanged.
+func (r Roster) Canonical(name, email string) string {
+	if email != "" {
+		for _, p := range r {
+			if containsFold(p.Emails, email) {
+				return p.Name
+			}
+		}
+	}
+	if name != "" {
+		for _, p := range r {
+			if strings.EqualFold(p.Name, name) || containsFold(p.Aliases, name) {
+				return p.Name
+			}
+		}
+	}
+	return name
+}
+
+// Apply replaces crumbs whose author has another canonical name with
+// copies carrying that name, for display. The originals are left alone, so
+// anything written back should be re-read with ReadCrumb.
+func (r Roster) Apply(crumbs []*Crumb) {
+	if len(r) == 0 {
+		return
+	}
+	for i, c := range crumbs {
+		if name := r.Canonical(c.Author, c.Email); name != c.Author {
+			display := *c
+			display.Author = name
+			crumbs[i] = &display
+		}
+	}
+}
+
+func containsFold(values []string, s string) bool {
+	s = strings.TrimSpace(s)
+	for _, v := range values {
+		if strings.EqualFold(strings.TrimSpace(v), s) {
+			return true
+		}
+	}
+	return false
+}
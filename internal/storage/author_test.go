@@ -0,0 +1,55 @@
+package storage
+
+import "testing"
+
+func TestGitIdentityPrefersEnvironment(t *testing.T) {
+	t.Setenv("GIT_AUTHOR_NAME", "Env Name")
+	t.Setenv("GIT_AUTHOR_EMAIL", "env@example.com")
+
+	id := GitIdentity()
+	if id.Name != "Env Name" || id.Email != "env@example.com" {
+		t.Errorf("expected identity from GIT_AUTHOR_*, got %+v", id)
+	}
+}
+
+func TestRosterCanonical(t *testing.T) {
+	roster := Roster{
+		{Name: "Ryan Snodgrass", Aliases: []string{"ryan", "rsnodgrass"}, Emails: []string{"ryan@work.com"}},
+	}
+
+	tests := []struct {
+		name, email, want string
+	}{
+		{"Ryan", "", "Ryan Snodgrass"},
+		{"laptop user", "RYAN@work.com", "Ryan Snodgrass"},
+		{"ryan snodgrass", "", "Ryan Snodgrass"},
+		{"Someone Else", "else@example.com", "Someone Else"},
+	}
+	for _, tt := range tests {
+		if got := roster.Canonical(tt.name, tt.email); got != tt.want {
+			t.Errorf("Canonical(%q, %q): expected %q, got %q", tt.name, tt.email, tt.want, got)
+		}
+	}
+}
+
+func TestListAppliesRoster(t *testing.T) {
+	useTempCache(t)
+	dir := t.TempDir()
+	s := NewMarkdownStorage(dir)
+	for _, author := range []Identity{{Name: "ryan"}, {Name: "Ryan S", Email: "ryan@work.com"}} {
+		if _, err := s.SaveCrumb(NewCrumb("p", "", "by "+author.Name, "Cursor", nil, author)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s.Roster = Roster{{Name: "Ryan Snodgrass", Aliases: []string{"ryan"}, Emails: []string{"ryan@work.com"}}}
+	crumbs, err := s.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range crumbs {
+		if c.Author != "Ryan Snodgrass" {
+			t.Errorf("expected canonical author for %q, got %q", c.Title, c.Author)
+		}
+	}
+}
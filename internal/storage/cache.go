@@ -0,0 +1,138 @@
+package storage
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/adrg/xdg"
+)
+
+// cacheVersion is bumped whenever the cached Crumb layout changes
+const cacheVersion = 5
+
+// indexCache is the on-disk index of parsed crumbs for one directory.
+// Entries are reused while a file's mtime and size are unchanged, and
+// re-parsed only when its content hash differs.
+type indexCache struct {
+	Version int                    `json:"version"`
+	Dir     string                 `json:"dir"`
+	Entries map[string]*cacheEntry `json:"entries"` // keyed by file name
+}
+
+type cacheEntry struct {
+	ModTime time.Time `json:"mod_time"`
+	Size    int64     `json:"size"`
+	Hash    string    `json:"hash"`
+	Crumb   *Crumb    `json:"crumb,omitempty"`
+	Err     string    `json:"err,omitempty"` // parse error, so bad files aren't re-read every run
+}
+
+// defaultCachePath returns the XDG cache file for a crumbs directory
+func defaultCachePath(baseDir string) string {
+	abs, err := filepath.Abs(baseDir)
+	if err != nil {
+		abs = baseDir
+	}
+	sum := sha256.Sum256([]byte(abs))
+	path, err := xdg.CacheFile(fmt.Sprintf("crumb/index-%s.json", hex.EncodeToString(sum[:8])))
+	if err != nil {
+		return ""
+	}
+	return path
+}
+
+// loadCache reads the cache file, returning an empty cache if it is
+// missing, unreadable or from another version.
+func loadCache(path, dir string) *indexCache {
+	empty := &indexCache{Version: cacheVersion, Dir: dir, Entries: make(map[string]*cacheEntry)}
+	if path == "" {
+		return empty
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return empty
+	}
+
+	var c indexCache
+	if err := json.Unmarshal(data, &c); err != nil || c.Version != cacheVersion || c.Dir != dir || c.Entries == nil {
+		return empty
+	}
+	return &c
+}
+
+// save writes the cache atomically so concurrent readers never see a
+// partial file
+func (c *indexCache) save(path string) error {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	return writeFileAtomic(path, data, 0644)
+}
+
+// refresh brings the cache in line with the directory contents and reports
+// whether anything changed.
+func (c *indexCache) refresh(dir string, entries []os.DirEntry) bool {
+	changed := false
+	present := make(map[string]bool, len(entries))
+
+	for _, entry := range entries {
+		if !isCrumbFile(entry) {
+			continue
+		}
+		name := entry.Name()
+		present[name] = true
+
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+
+		cached := c.Entries[name]
+		if cached != nil && cached.ModTime.Equal(info.ModTime()) && cached.Size == info.Size() {
+			continue
+		}
+
+		path := filepath.Join(dir, name)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		sum := sha256.Sum256(data)
+		hash := hex.EncodeToString(sum[:])
+
+		if cached == nil || cached.Hash != hash {
+			cached = &cacheEntry{Hash: hash}
+			if crumb, err := Unmarshal(data); err != nil {
+				cached.Err = err.Error()
+			} else {
+				crumb.Path = path
+				cached.Crumb = crumb
+			}
+		}
+		cached.ModTime = info.ModTime()
+		cached.Size = info.Size()
+		c.Entries[name] = cached
+		changed = true
+	}
+
+	for name := range c.Entries {
+		if !present[name] {
+			delete(c.Entries, name)
+			changed = true
+		}
+	}
+
+	return changed
+}
@@ -0,0 +1,68 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/adrg/xdg"
+)
+
+// useTempCache points the XDG cache at a temporary directory
+func useTempCache(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("XDG_CACHE_HOME", dir)
+	xdg.Reload()
+	t.Cleanup(xdg.Reload)
+	return dir
+}
+
+func TestListUsesIndexCache(t *testing.T) {
+	useTempCache(t)
+	dir := t.TempDir()
+	s := NewMarkdownStorage(dir)
+
+	c := &Crumb{Title: "Cached", Date: time.Now(), Tags: []string{"one"}, Prompt: "p"}
+	path, err := s.SaveCrumb(c)
+	if err != nil {
+		t.Fatalf("SaveCrumb failed: %v", err)
+	}
+
+	if _, err := s.List(); err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if _, err := os.Stat(s.cachePath); err != nil {
+		t.Fatalf("expected cache file to be written: %v", err)
+	}
+
+	cache := loadCache(s.cachePath, dir)
+	entry := cache.Entries[filepath.Base(path)]
+	if entry == nil || entry.Crumb == nil || entry.Crumb.Title != "Cached" {
+		t.Fatalf("expected cached entry for %s, got %+v", path, entry)
+	}
+
+	// rewriting the file must invalidate the entry
+	c.Tags = []string{"two"}
+	data, _ := c.Marshal()
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	future := time.Now().Add(time.Minute)
+	os.Chtimes(path, future, future)
+
+	if tags := s.GetFrequentTags(5); len(tags) != 1 || tags[0] != "two" {
+		t.Errorf("expected refreshed tags [two], got %v", tags)
+	}
+
+	// deleted files drop out of the index
+	os.Remove(path)
+	crumbs, err := s.List()
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(crumbs) != 0 {
+		t.Errorf("expected no crumbs after delete, got %d", len(crumbs))
+	}
+}
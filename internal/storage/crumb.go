@@ -0,0 +1,323 @@
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const frontmatterDelim = "---"
+
+// Crumb is a single captured prompt: YAML frontmatter followed by a markdown
+// body with an optional "## System" section, "## Prompt" and an optional
+// "## Output" section.
+type Crumb struct {
+	Title   string    `yaml:"title"`
+	Date    time.Time `yaml:"date"`
+	Updated time.Time `yaml:"updated,omitempty"`
+	Author  string    `yaml:"author"`
+	Email   string    `yaml:"email,omitempty"` // author's email
+	Tool    string    `yaml:"tool"`
+	Tags    []string  `yaml:"tags,omitempty"`
+
+	// Git is the code state the prompt was used on, when recorded
+	Git *GitContext `yaml:"git,omitempty"`
+
+	// ImportID identifies the conversation turn an imported crumb came
+	// from, so importing the same log again skips it
+	ImportID string `yaml:"import_id,omitempty"`
+
+	// Variables declares the {{name}} placeholders in a reusable prompt
+	Variables []Variable `yaml:"variables,omitempty"`
+
+	// Extra holds frontmatter keys crumb doesn't know about so they survive
+	// a read/write round trip.
+	Extra map[string]interface{} `yaml:",inline"`
+
+	System string `yaml:"-"` // system prompt the prompt was sent with
+	Prompt string `yaml:"-"`
+	Output string `yaml:"-"`
+
+	// Path is the file the crumb was read from (empty for unsaved crumbs)
+	Path string `yaml:"-"`
+}
+
+// NewCrumb builds a crumb stamped with the current time and the given
+// author (see GitIdentity), "Unknown" if it has no name. An empty title is
+// generated from the prompt.
+func NewCrumb(prompt, output, title, tool string, tags []string, author Identity) *Crumb {
+	title = strings.TrimSpace(title)
+	if title == "" {
+		title = GenerateTitle(prompt)
+	}
+
+	if author.Name == "" {
+		author.Name = "Unknown"
+	}
+
+	return &Crumb{
+		Title:  title,
+		Date:   GetTimestamp(),
+		Author: author.Name,
+		Email:  author.Email,
+		Tool:   tool,
+		Tags:   tags,
+		Prompt: prompt,
+		Output: output,
+	}
+}
+
+// CopyText returns the prompt body for pasting into a tool, optionally
+// followed by the recorded output.
+func (c *Crumb) CopyText(withOutput bool) string {
+	if !withOutput || strings.TrimSpace(c.Output) == "" {
+		return c.Prompt
+	}
+	return c.Prompt + "\n\n## Output\n\n" + c.Output
+}
+
+// Marshal renders the crumb as markdown with YAML frontmatter.
+func (c *Crumb) Marshal() ([]byte, error) {
+	fm := *c
+	fm.Date = c.Date.Truncate(time.Second)
+	fm.Updated = c.Updated.Truncate(time.Second)
+
+	var front bytes.Buffer
+	enc := yaml.NewEncoder(&front)
+	enc.SetIndent(2)
+	if err := enc.Encode(&fm); err != nil {
+		return nil, fmt.Errorf("failed to encode frontmatter: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encode frontmatter: %w", err)
+	}
+
+	var b bytes.Buffer
+	b.WriteString(frontmatterDelim + "\n")
+	b.Write(front.Bytes())
+	b.WriteString(frontmatterDelim + "\n\n")
+	b.WriteString(fmt.Sprintf("# %s\n\n", c.Title))
+	if strings.TrimSpace(c.System) != "" {
+		b.WriteString("## System\n\n")
+		b.WriteString(escapeSections(c.System))
+		b.WriteString("\n\n")
+	}
+	b.WriteString("## Prompt\n\n")
+	b.WriteString(escapeSections(c.Prompt))
+	b.WriteString("\n\n")
+
+	if strings.TrimSpace(c.Output) != "" {
+		b.WriteString("## Output\n\n")
+		b.WriteString(escapeSections(c.Output))
+		b.WriteString("\n")
+	}
+
+	return b.Bytes(), nil
+}
+
+// Unmarshal parses markdown with YAML frontmatter into a Crumb.
+func Unmarshal(data []byte) (*Crumb, error) {
+	front, body, err := splitFrontmatter(string(data))
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Crumb{}
+	if err := yaml.Unmarshal([]byte(front), c); err != nil {
+		// crumbs written before the structured encoder could contain
+		// unquoted colons in the title, so fall back to a line parser
+		legacy, lerr := parseLegacyFrontmatter(front)
+		if lerr != nil {
+			return nil, fmt.Errorf("failed to parse frontmatter: %w", err)
+		}
+		c = legacy
+	}
+
+	c.System, c.Prompt, c.Output = parseSections(body)
+	return c, nil
+}
+
+// ReadCrumb reads and parses the crumb file at path.
+func ReadCrumb(path string) (*Crumb, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	c, err := Unmarshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	c.Path = path
+	return c, nil
+}
+
+// splitFrontmatter separates the YAML frontmatter from the markdown body.
+func splitFrontmatter(s string) (string, string, error) {
+	s = strings.TrimPrefix(s, "\ufeff")
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+
+	if !strings.HasPrefix(s, frontmatterDelim+"\n") {
+		return "", "", fmt.Errorf("missing frontmatter")
+	}
+	rest := s[len(frontmatterDelim)+1:]
+
+	// closing delimiter may be immediately after the opening one
+	if strings.HasPrefix(rest, frontmatterDelim+"\n") {
+		return "", rest[len(frontmatterDelim)+1:], nil
+	}
+
+	end := strings.Index(rest, "\n"+frontmatterDelim+"\n")
+	if end < 0 {
+		if strings.HasSuffix(rest, "\n"+frontmatterDelim) {
+			return rest[:len(rest)-len(frontmatterDelim)-1], "", nil
+		}
+		return "", "", fmt.Errorf("unterminated frontmatter")
+	}
+
+	return rest[:end], rest[end+len(frontmatterDelim)+2:], nil
+}
+
+// parseSections extracts the system, prompt and output bodies. Headings
+// inside fenced code blocks are ignored so pasted markdown doesn't split
+// sections.
+func parseSections(body string) (string, string, string) {
+	var system, prompt, output []string
+	var current *[]string
+	inFence := false
+
+	scanner := bufio.NewScanner(strings.NewReader(body))
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+		}
+
+		if !inFence {
+			switch {
+			case current == nil && trimmed == "## System":
+				current = &system
+				continue
+			case (current == nil || current == &system) && trimmed == "## Prompt":
+				current = &prompt
+				continue
+			case current == &prompt && trimmed == "## Output":
+				current = &output
+				continue
+			}
+		}
+
+		if current != nil {
+			*current = append(*current, line)
+		}
+	}
+
+	return unescapeSections(trimBlankLines(system)), unescapeSections(trimBlankLines(prompt)), unescapeSections(trimBlankLines(output))
+}
+
+// sectionHeadings are the headings parseSections splits the body on
+var sectionHeadings = []string{"## System", "## Prompt", "## Output"}
+
+// escapeSections backslash-escapes lines in a section body that would read
+// as a section heading ("## Output" becomes "\## Output", which markdown
+// still renders as the literal text). Lines that already look escaped get
+// another backslash, so unescapeSections restores any body exactly.
+func escapeSections(body string) string {
+	return mapHeadingLines(body, func(indent, line string) string {
+		return indent + `\` + line
+	})
+}
+
+// unescapeSections reverses escapeSections
+func unescapeSections(body string) string {
+	return mapHeadingLines(body, func(indent, line string) string {
+		return indent + strings.TrimPrefix(line, `\`)
+	})
+}
+
+// mapHeadingLines applies fn to each line that is a section heading after
+// any leading backslashes, passing its indentation and the rest separately
+func mapHeadingLines(body string, fn func(indent, line string) string) string {
+	if !strings.Contains(body, "## ") {
+		return body
+	}
+	lines := strings.Split(body, "\n")
+	for i, line := range lines {
+		rest := strings.TrimLeft(line, " \t")
+		if slices.Contains(sectionHeadings, strings.TrimSpace(strings.TrimLeft(rest, `\`))) {
+			lines[i] = fn(line[:len(line)-len(rest)], rest)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// trimBlankLines joins lines, dropping leading and trailing blank lines
+// while keeping indentation of the content itself.
+func trimBlankLines(lines []string) string {
+	start, end := 0, len(lines)
+	for start < end && strings.TrimSpace(lines[start]) == "" {
+		start++
+	}
+	for end > start && strings.TrimSpace(lines[end-1]) == "" {
+		end--
+	}
+	return strings.Join(lines[start:end], "\n")
+}
+
+// parseLegacyFrontmatter reads the hand-built "key: value" frontmatter that
+// older versions wrote without quoting.
+func parseLegacyFrontmatter(front string) (*Crumb, error) {
+	c := &Crumb{}
+	inTags := false
+
+	for _, line := range strings.Split(front, "\n") {
+		if inTags {
+			if strings.HasPrefix(line, "  - ") {
+				if tag := strings.TrimSpace(strings.TrimPrefix(line, "  - ")); tag != "" {
+					c.Tags = append(c.Tags, tag)
+				}
+				continue
+			}
+			inTags = false
+		}
+
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+
+		switch strings.TrimSpace(key) {
+		case "title":
+			c.Title = value
+		case "date":
+			t, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid date %q: %w", value, err)
+			}
+			c.Date = t
+		case "author":
+			c.Author = value
+		case "email":
+			c.Email = value
+		case "tool":
+			c.Tool = value
+		case "tags":
+			inTags = value == ""
+		}
+	}
+
+	if c.Title == "" {
+		return nil, fmt.Errorf("missing title")
+	}
+	return c, nil
+}
@@ -0,0 +1,291 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCrumbRoundTrip(t *testing.T) {
+	original := &Crumb{
+		Title:  `Fix: "quoted" title with colons: everywhere`,
+		Date:   time.Date(2024, 12, 3, 14, 32, 0, 0, time.UTC),
+		Author: "Ryan",
+		Tool:   "Claude Code",
+		Tags:   []string{"debugging", "go"},
+		Extra:  map[string]interface{}{"model": "opus"},
+		Prompt: "Why does this panic?\n\n```go\n## Output\nfmt.Println(x)\n```",
+		Output: "Because x is nil.\n\n## Details\n\nMore text.",
+	}
+
+	data, err := original.Marshal()
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	parsed, err := Unmarshal(data)
+	if err != nil {
+		t.Fatalf("Unmarshal failed: %v\n%s", err, data)
+	}
+
+	if parsed.Title != original.Title {
+		t.Errorf("expected title %q, got %q", original.Title, parsed.Title)
+	}
+	if !parsed.Date.Equal(original.Date) {
+		t.Errorf("expected date %v, got %v", original.Date, parsed.Date)
+	}
+	if parsed.Author != original.Author || parsed.Tool != original.Tool {
+		t.Errorf("expected author/tool %q/%q, got %q/%q", original.Author, original.Tool, parsed.Author, parsed.Tool)
+	}
+	if !reflect.DeepEqual(parsed.Tags, original.Tags) {
+		t.Errorf("expected tags %v, got %v", original.Tags, parsed.Tags)
+	}
+	if parsed.Extra["model"] != "opus" {
+		t.Errorf("expected extra field to survive, got %v", parsed.Extra)
+	}
+	if parsed.Prompt != original.Prompt {
+		t.Errorf("expected prompt %q, got %q", original.Prompt, parsed.Prompt)
+	}
+	if parsed.Output != original.Output {
+		t.Errorf("expected output %q, got %q", original.Output, parsed.Output)
+	}
+}
+
+func TestCrumbSystemSection(t *testing.T) {
+	original := &Crumb{
+		Title:  "With system",
+		Date:   time.Date(2024, 12, 3, 14, 32, 0, 0, time.UTC),
+		System: "You are a Go reviewer.\n\n## Rules\n\nBe brief.",
+		Prompt: "Review this",
+		Output: "Looks fine.",
+	}
+
+	data, err := original.Marshal()
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	parsed, err := Unmarshal(data)
+	if err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	if parsed.System != original.System || parsed.Prompt != original.Prompt || parsed.Output != original.Output {
+		t.Errorf("expected sections to round trip, got %q/%q/%q", parsed.System, parsed.Prompt, parsed.Output)
+	}
+}
+
+func TestCrumbSectionHeadingsInBody(t *testing.T) {
+	original := &Crumb{
+		Title:  "Headings",
+		Date:   time.Date(2024, 12, 3, 14, 32, 0, 0, time.UTC),
+		System: "## Prompt\nfirst",
+		Prompt: "line\n## Output\nmore\n  \\## Output\n",
+		Output: "## System\nend",
+	}
+
+	data, err := original.Marshal()
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if !strings.Contains(string(data), "\n\\## Output\n") {
+		t.Errorf("expected the heading in the prompt escaped:\n%s", data)
+	}
+	parsed, err := Unmarshal(data)
+	if err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	if parsed.System != original.System || parsed.Prompt != strings.TrimSpace(original.Prompt) || parsed.Output != original.Output {
+		t.Errorf("expected sections to round trip, got %q/%q/%q", parsed.System, parsed.Prompt, parsed.Output)
+	}
+}
+
+func TestCrumbMarshalFormat(t *testing.T) {
+	c := &Crumb{
+		Title:  "Simple title",
+		Date:   time.Date(2024, 12, 3, 14, 32, 5, 123, time.UTC),
+		Author: "Ryan",
+		Tool:   "Cursor",
+		Tags:   []string{"design"},
+		Prompt: "Do the thing",
+	}
+
+	data, err := c.Marshal()
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	expected := `---
+title: Simple title
+date: 2024-12-03T14:32:05Z
+author: Ryan
+tool: Cursor
+tags:
+  - design
+---
+
+# Simple title
+
+## Prompt
+
+Do the thing
+
+`
+	if string(data) != expected {
+		t.Errorf("unexpected output:\n%s\nexpected:\n%s", data, expected)
+	}
+}
+
+func TestUnmarshalLegacyFrontmatter(t *testing.T) {
+	// older versions wrote titles without quoting
+	legacy := `---
+title: Fix: broken yaml
+date: 2024-12-03T14:32:00-08:00
+author: Ryan
+tool: Claude Code
+tags:
+  - debugging
+---
+
+# Fix: broken yaml
+
+## Prompt
+
+hello
+`
+
+	c, err := Unmarshal([]byte(legacy))
+	if err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	if c.Title != "Fix: broken yaml" {
+		t.Errorf("expected legacy title, got %q", c.Title)
+	}
+	if len(c.Tags) != 1 || c.Tags[0] != "debugging" {
+		t.Errorf("expected [debugging], got %v", c.Tags)
+	}
+	if c.Prompt != "hello" {
+		t.Errorf("expected prompt 'hello', got %q", c.Prompt)
+	}
+	if c.Output != "" {
+		t.Errorf("expected empty output, got %q", c.Output)
+	}
+}
+
+func TestUnmarshalMissingFrontmatter(t *testing.T) {
+	if _, err := Unmarshal([]byte("# just markdown\n")); err == nil {
+		t.Error("expected error for file without frontmatter")
+	}
+}
+
+func TestMarkdownStorageList(t *testing.T) {
+	useTempCache(t)
+	dir := t.TempDir()
+	s := NewMarkdownStorage(dir)
+
+	c := &Crumb{
+		Title:  "Listed crumb",
+		Date:   time.Now(),
+		Author: "Ryan",
+		Tool:   "Aider",
+		Prompt: "prompt",
+	}
+	path, err := s.SaveCrumb(c)
+	if err != nil {
+		t.Fatalf("SaveCrumb failed: %v", err)
+	}
+	if !strings.HasSuffix(path, "-listed-crumb.md") {
+		t.Errorf("unexpected filename %s", path)
+	}
+
+	// README and unparseable files are ignored
+	os.WriteFile(filepath.Join(dir, "README.md"), []byte("# index\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "notes.md"), []byte("no frontmatter\n"), 0644)
+
+	crumbs, err := s.List()
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(crumbs) != 1 {
+		t.Fatalf("expected 1 crumb, got %d", len(crumbs))
+	}
+	if crumbs[0].Title != "Listed crumb" || crumbs[0].Path != path {
+		t.Errorf("unexpected crumb %+v", crumbs[0])
+	}
+}
+
+func TestMarkdownStorageUpdate(t *testing.T) {
+	useTempCache(t)
+	dir := t.TempDir()
+	s := NewMarkdownStorage(dir)
+
+	date := time.Date(2024, 12, 3, 14, 32, 0, 0, time.UTC)
+	c := &Crumb{
+		Title:  "Original title",
+		Date:   date,
+		Author: "Ryan",
+		Tool:   "Cursor",
+		Extra:  map[string]interface{}{"team": "infra"},
+		Prompt: "p",
+	}
+	oldPath, err := s.SaveCrumb(c)
+	if err != nil {
+		t.Fatalf("SaveCrumb failed: %v", err)
+	}
+
+	loaded, err := ReadCrumb(oldPath)
+	if err != nil {
+		t.Fatalf("ReadCrumb failed: %v", err)
+	}
+	loaded.Title = "Renamed title"
+	loaded.Prompt = "edited"
+
+	newPath, err := s.Update(loaded)
+	if err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+
+	if filepath.Base(newPath) != "2024-12-03-renamed-title.md" {
+		t.Errorf("expected rename keeping the original date, got %s", newPath)
+	}
+	if _, err := os.Stat(oldPath); !os.IsNotExist(err) {
+		t.Error("expected old file to be removed")
+	}
+
+	updated, err := ReadCrumb(newPath)
+	if err != nil {
+		t.Fatalf("ReadCrumb failed: %v", err)
+	}
+	if !updated.Date.Equal(date) || updated.Author != "Ryan" {
+		t.Errorf("expected original date/author, got %v/%s", updated.Date, updated.Author)
+	}
+	if updated.Updated.IsZero() {
+		t.Error("expected updated timestamp")
+	}
+	if updated.Extra["team"] != "infra" {
+		t.Errorf("expected unknown keys to survive, got %v", updated.Extra)
+	}
+	if updated.Prompt != "edited" {
+		t.Errorf("expected edited prompt, got %q", updated.Prompt)
+	}
+}
+
+func TestCopyText(t *testing.T) {
+	c := &Crumb{Prompt: "do the thing", Output: "done"}
+
+	if got := c.CopyText(false); got != "do the thing" {
+		t.Errorf("expected prompt only, got %q", got)
+	}
+	if got := c.CopyText(true); got != "do the thing\n\n## Output\n\ndone" {
+		t.Errorf("expected prompt and output, got %q", got)
+	}
+
+	c.Output = ""
+	if got := c.CopyText(true); got != "do the thing" {
+		t.Errorf("expected prompt only without output, got %q", got)
+	}
+}
@@ -1,22 +1,17 @@
 package storage
 
 import (
-	"os/exec"
 	"regexp"
 	"strings"
 	"time"
 	"unicode"
 )
 
-// GetGitAuthor runs `git config user.name` and returns the configured name.
-// Returns empty string if git is not available or user.name is not configured.
+// GetGitAuthor returns the name git would record as the author (see
+// GitIdentity). Returns empty string if git is not available or user.name
+// is not configured.
 func GetGitAuthor() string {
-	cmd := exec.Command("git", "config", "user.name")
-	output, err := cmd.Output()
-	if err != nil {
-		return ""
-	}
-	return strings.TrimSpace(string(output))
+	return GitIdentity().Name
 }
 
 // GenerateTitle creates a title from the prompt by taking approximately the first 60 characters,
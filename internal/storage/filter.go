@@ -0,0 +1,91 @@
+package storage
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Filter selects crumbs by metadata. Zero-valued fields match everything.
+type Filter struct {
+	Tags   []string  // crumb must carry all of these tags
+	Tool   string    // exact tool name, case-insensitive
+	Author string    // substring of the author name or email, case-insensitive
+	Since  time.Time // inclusive lower bound on Date
+	Until  time.Time // inclusive upper bound on Date
+}
+
+// Match reports whether the crumb satisfies every condition of the filter.
+func (f Filter) Match(c *Crumb) bool {
+	if f.Tool != "" && !strings.EqualFold(c.Tool, f.Tool) {
+		return false
+	}
+	if f.Author != "" && !containsLower(c.Author, f.Author) && !containsLower(c.Email, f.Author) {
+		return false
+	}
+	if !f.Since.IsZero() && c.Date.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && c.Date.After(f.Until) {
+		return false
+	}
+	for _, want := range f.Tags {
+		if !hasTag(c, want) {
+			return false
+		}
+	}
+	return true
+}
+
+// FilterCrumbs returns the crumbs matching f, preserving order.
+func FilterCrumbs(crumbs []*Crumb, f Filter) []*Crumb {
+	result := make([]*Crumb, 0, len(crumbs))
+	for _, c := range crumbs {
+		if f.Match(c) {
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+// SortCrumbs sorts crumbs in place by "date" (newest first), "title",
+// "author" or "tool". reverse flips the resulting order.
+func SortCrumbs(crumbs []*Crumb, by string, reverse bool) error {
+	var less func(a, b *Crumb) bool
+
+	switch by {
+	case "", "date":
+		less = func(a, b *Crumb) bool { return a.Date.After(b.Date) }
+	case "title":
+		less = func(a, b *Crumb) bool { return strings.ToLower(a.Title) < strings.ToLower(b.Title) }
+	case "author":
+		less = func(a, b *Crumb) bool { return strings.ToLower(a.Author) < strings.ToLower(b.Author) }
+	case "tool":
+		less = func(a, b *Crumb) bool { return strings.ToLower(a.Tool) < strings.ToLower(b.Tool) }
+	default:
+		return fmt.Errorf("unknown sort field: %s (use date, title, author or tool)", by)
+	}
+
+	sort.SliceStable(crumbs, func(i, j int) bool {
+		if reverse {
+			return less(crumbs[j], crumbs[i])
+		}
+		return less(crumbs[i], crumbs[j])
+	})
+	return nil
+}
+
+func hasTag(c *Crumb, tag string) bool {
+	for _, t := range c.Tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsLower reports whether s contains substr, ignoring case
+func containsLower(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
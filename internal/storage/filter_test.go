@@ -0,0 +1,65 @@
+package storage
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFilterMatch(t *testing.T) {
+	c := &Crumb{
+		Title:  "Debug flaky test",
+		Date:   time.Date(2024, 12, 3, 12, 0, 0, 0, time.UTC),
+		Author: "Ryan Snodgrass",
+		Tool:   "Cursor",
+		Tags:   []string{"debugging", "testing"},
+	}
+
+	tests := []struct {
+		name   string
+		filter Filter
+		want   bool
+	}{
+		{"empty filter", Filter{}, true},
+		{"tool case-insensitive", Filter{Tool: "cursor"}, true},
+		{"wrong tool", Filter{Tool: "Aider"}, false},
+		{"author substring", Filter{Author: "ryan"}, true},
+		{"all tags present", Filter{Tags: []string{"testing", "Debugging"}}, true},
+		{"missing tag", Filter{Tags: []string{"design"}}, false},
+		{"since before", Filter{Since: time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC)}, true},
+		{"since after", Filter{Since: time.Date(2024, 12, 4, 0, 0, 0, 0, time.UTC)}, false},
+		{"until after", Filter{Until: time.Date(2024, 12, 4, 0, 0, 0, 0, time.UTC)}, true},
+		{"until before", Filter{Until: time.Date(2024, 12, 2, 0, 0, 0, 0, time.UTC)}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Match(c); got != tt.want {
+				t.Errorf("Match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSortCrumbs(t *testing.T) {
+	older := &Crumb{Title: "b", Date: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	newer := &Crumb{Title: "a", Date: time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)}
+
+	crumbs := []*Crumb{older, newer}
+	if err := SortCrumbs(crumbs, "date", false); err != nil {
+		t.Fatalf("SortCrumbs failed: %v", err)
+	}
+	if crumbs[0] != newer {
+		t.Error("expected newest crumb first")
+	}
+
+	if err := SortCrumbs(crumbs, "title", true); err != nil {
+		t.Fatalf("SortCrumbs failed: %v", err)
+	}
+	if crumbs[0] != older {
+		t.Error("expected reversed title order")
+	}
+
+	if err := SortCrumbs(crumbs, "bogus", false); err == nil {
+		t.Error("expected error for unknown sort field")
+	}
+}
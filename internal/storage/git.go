@@ -0,0 +1,233 @@
+package storage
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// DefaultCommitMessage is used when no commit message template is configured
+const DefaultCommitMessage = "crumb: {{.Title}}"
+
+// GitCommitter stages and commits files written to a crumbs directory,
+// either on the checked-out branch or on a dedicated branch that is
+// updated without touching the working tree.
+type GitCommitter struct {
+	message *template.Template
+	branch  string
+}
+
+// CommitData is passed to the commit message template
+type CommitData struct {
+	Title  string // crumb title, or a description of the change
+	Action string // "add", "update" or "readme"
+}
+
+// CommitError reports a failed commit after the file itself was saved
+type CommitError struct {
+	Err error
+}
+
+func (e *CommitError) Error() string {
+	return "saved, but failed to commit: " + e.Err.Error()
+}
+
+func (e *CommitError) Unwrap() error {
+	return e.Err
+}
+
+// NewGitCommitter parses the message template (DefaultCommitMessage when
+// empty). An empty branch commits on the current branch.
+func NewGitCommitter(message, branch string) (*GitCommitter, error) {
+	if message == "" {
+		message = DefaultCommitMessage
+	}
+	tmpl, err := template.New("commit").Parse(message)
+	if err != nil {
+		return nil, fmt.Errorf("invalid commit message template: %w", err)
+	}
+	return &GitCommitter{message: tmpl, branch: branch}, nil
+}
+
+// Commit commits the given files (which may include deleted paths) with a
+// message rendered from data. It does nothing when none of them changed.
+func (g *GitCommitter) Commit(data CommitData, paths ...string) error {
+	if g == nil || len(paths) == 0 {
+		return nil
+	}
+
+	var msg bytes.Buffer
+	if err := g.message.Execute(&msg, data); err != nil {
+		return &CommitError{fmt.Errorf("failed to render commit message: %w", err)}
+	}
+
+	dir := filepath.Dir(paths[0])
+	top, err := git(dir, nil, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return &CommitError{fmt.Errorf("%s is not in a git repository", dir)}
+	}
+
+	rel, err := relativePaths(top, paths)
+	if err != nil {
+		return &CommitError{err}
+	}
+
+	current, _ := git(top, nil, "symbolic-ref", "--quiet", "--short", "HEAD")
+	if g.branch == "" || g.branch == current {
+		err = commitOnHead(top, rel, msg.String())
+	} else {
+		err = commitOnBranch(top, g.branch, rel, msg.String())
+	}
+	if err != nil {
+		return &CommitError{err}
+	}
+	return nil
+}
+
+// commitOnHead commits just these paths on the current branch, leaving
+// anything else the user has staged alone
+func commitOnHead(top string, paths []string, message string) error {
+	var commit []string
+	for _, p := range paths {
+		_, statErr := os.Stat(filepath.Join(top, p))
+		if _, err := git(top, nil, "ls-files", "--error-unmatch", "--", p); err != nil && statErr != nil {
+			continue // deleted and never tracked
+		}
+		commit = append(commit, p)
+	}
+	if len(commit) == 0 {
+		return nil
+	}
+
+	status, err := git(top, nil, append([]string{"status", "--porcelain", "--"}, commit...)...)
+	if err != nil {
+		return err
+	}
+	if status == "" {
+		return nil
+	}
+
+	if _, err := git(top, nil, append([]string{"add", "-A", "--"}, commit...)...); err != nil {
+		return err
+	}
+	_, err = git(top, nil, append([]string{"commit", "--quiet", "--only", "-m", message, "--"}, commit...)...)
+	return err
+}
+
+// commitOnBranch records the paths in a commit on branch using a scratch
+// index, so the checked-out branch and working tree are untouched. A
+// missing branch starts from HEAD.
+func commitOnBranch(top, branch string, paths []string, message string) error {
+	ref := "refs/heads/" + branch
+	parent, err := git(top, nil, "rev-parse", "--verify", "--quiet", ref)
+	// the ref's value now, which update-ref checks before moving it; empty
+	// requires the branch to still not exist
+	expected := parent
+	if err != nil {
+		parent, _ = git(top, nil, "rev-parse", "--verify", "--quiet", "HEAD")
+	}
+
+	index, err := os.CreateTemp("", "crumb-index-*")
+	if err != nil {
+		return err
+	}
+	index.Close()
+	os.Remove(index.Name()) // git refuses an empty index file
+	defer os.Remove(index.Name())
+	env := []string{"GIT_INDEX_FILE=" + index.Name()}
+
+	if parent != "" {
+		if _, err := git(top, env, "read-tree", parent); err != nil {
+			return err
+		}
+	}
+
+	for _, p := range paths {
+		if _, err := os.Stat(filepath.Join(top, p)); err != nil {
+			if _, err := git(top, env, "update-index", "--force-remove", "--", p); err != nil {
+				return err
+			}
+			continue
+		}
+
+		blob, err := git(top, nil, "hash-object", "-w", "--", p)
+		if err != nil {
+			return err
+		}
+		if _, err := git(top, env, "update-index", "--add", "--cacheinfo", "100644,"+blob+","+filepath.ToSlash(p)); err != nil {
+			return err
+		}
+	}
+
+	tree, err := git(top, env, "write-tree")
+	if err != nil {
+		return err
+	}
+	if parent != "" {
+		if parentTree, _ := git(top, nil, "rev-parse", parent+"^{tree}"); parentTree == tree {
+			return nil
+		}
+	}
+
+	args := []string{"commit-tree", tree, "-m", message}
+	if parent != "" {
+		args = append(args, "-p", parent)
+	}
+	commit, err := git(top, nil, args...)
+	if err != nil {
+		return err
+	}
+
+	// only move the branch if nobody else did meanwhile
+	_, err = git(top, nil, "update-ref", ref, commit, expected)
+	return err
+}
+
+// relativePaths makes paths relative to the work tree root
+func relativePaths(top string, paths []string) ([]string, error) {
+	root, err := filepath.EvalSymlinks(top)
+	if err != nil {
+		return nil, err
+	}
+
+	rel := make([]string, len(paths))
+	for i, p := range paths {
+		abs, err := filepath.Abs(p)
+		if err != nil {
+			return nil, err
+		}
+		// resolve symlinks via the directory, which still exists for deleted files
+		if dir, err := filepath.EvalSymlinks(filepath.Dir(abs)); err == nil {
+			abs = filepath.Join(dir, filepath.Base(abs))
+		}
+		if rel[i], err = filepath.Rel(root, abs); err != nil || strings.HasPrefix(rel[i], "..") {
+			return nil, fmt.Errorf("%s is outside the git repository", p)
+		}
+	}
+	return rel, nil
+}
+
+// git runs a git command in dir, like GetGitAuthor, returning trimmed
+// stdout or an error carrying git's stderr
+func git(dir string, env []string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), env...)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && stderr.Len() > 0 {
+			return "", fmt.Errorf("git %s: %s", args[0], strings.TrimSpace(stderr.String()))
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
@@ -0,0 +1,111 @@
+package storage
+
+import (
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// newTestRepo creates a git repository with one commit and a crumbs directory
+func newTestRepo(t *testing.T) (repo, crumbs string) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	useTempCache(t)
+
+	repo = t.TempDir()
+	for _, args := range [][]string{
+		{"init", "--quiet", "--initial-branch=main"},
+		{"config", "user.name", "Test"},
+		{"config", "user.email", "test@example.com"},
+		{"commit", "--quiet", "--allow-empty", "-m", "initial"},
+	} {
+		if _, err := git(repo, nil, args...); err != nil {
+			t.Fatalf("git %v failed: %v", args, err)
+		}
+	}
+	return repo, filepath.Join(repo, "crumbs")
+}
+
+func TestSaveCrumbCommits(t *testing.T) {
+	repo, dir := newTestRepo(t)
+
+	committer, err := NewGitCommitter("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewMarkdownStorage(dir)
+	s.Git = committer
+
+	c := &Crumb{Title: "Fix flaky test", Date: time.Now(), Prompt: "p"}
+	if _, err := s.SaveCrumb(c); err != nil {
+		t.Fatalf("SaveCrumb failed: %v", err)
+	}
+
+	subject, _ := git(repo, nil, "log", "-1", "--format=%s")
+	if subject != "crumb: Fix flaky test" {
+		t.Errorf("expected templated commit message, got %q", subject)
+	}
+	if status, _ := git(repo, nil, "status", "--porcelain", "--", "crumbs/"+filepath.Base(c.Path)); status != "" {
+		t.Errorf("expected crumb to be committed, got status %q", status)
+	}
+}
+
+func TestSaveCrumbCommitsOnBranch(t *testing.T) {
+	repo, dir := newTestRepo(t)
+	head, _ := git(repo, nil, "rev-parse", "HEAD")
+
+	committer, err := NewGitCommitter("add {{.Title}}", "crumbs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewMarkdownStorage(dir)
+	s.Git = committer
+
+	c := &Crumb{Title: "Design review", Date: time.Now(), Prompt: "p"}
+	if _, err := s.SaveCrumb(c); err != nil {
+		t.Fatalf("SaveCrumb failed: %v", err)
+	}
+
+	if now, _ := git(repo, nil, "rev-parse", "HEAD"); now != head {
+		t.Error("expected the checked-out branch to be untouched")
+	}
+	files, err := git(repo, nil, "ls-tree", "-r", "--name-only", "refs/heads/crumbs")
+	if err != nil {
+		t.Fatalf("expected crumbs branch to exist: %v", err)
+	}
+	if files != "crumbs/"+filepath.Base(c.Path) {
+		t.Errorf("expected crumb on branch, got %q", files)
+	}
+	if subject, _ := git(repo, nil, "log", "-1", "--format=%s", "refs/heads/crumbs"); subject != "add Design review" {
+		t.Errorf("expected templated commit message, got %q", subject)
+	}
+
+	// a second save moves the existing branch
+	if _, err := s.SaveCrumb(&Crumb{Title: "Retro", Date: time.Now(), Prompt: "p"}); err != nil {
+		t.Fatalf("SaveCrumb failed: %v", err)
+	}
+	if count, _ := git(repo, nil, "rev-list", "--count", "refs/heads/crumbs"); count != "3" {
+		t.Errorf("expected two crumb commits on the branch, got %s commits", count)
+	}
+}
+
+func TestCommitOutsideRepository(t *testing.T) {
+	useTempCache(t)
+	committer, err := NewGitCommitter("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewMarkdownStorage(t.TempDir())
+	s.Git = committer
+
+	path, err := s.SaveCrumb(&Crumb{Title: "Loose", Date: time.Now(), Prompt: "p"})
+	if _, ok := err.(*CommitError); !ok {
+		t.Fatalf("expected a CommitError, got %v", err)
+	}
+	if path == "" {
+		t.Error("expected the crumb to be saved anyway")
+	}
+}
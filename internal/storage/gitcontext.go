@@ -0,0 +1,166 @@
+package storage
+
+import (
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// GitContext records the state of the code a prompt was used on
+type GitContext struct {
+	Remote  string   `yaml:"remote,omitempty"`  // origin (or first remote) URL
+	Branch  string   `yaml:"branch,omitempty"`  // empty on a detached HEAD
+	Commit  string   `yaml:"commit,omitempty"`  // HEAD commit SHA
+	Changed []string `yaml:"changed,omitempty"` // staged, modified and untracked files
+
+	// ChangedCount is the number of changed files when there were more
+	// than maxChanged, so only the first ones are listed
+	ChangedCount int `yaml:"changed_count,omitempty"`
+}
+
+// maxChanged caps the changed files listed in a crumb
+const maxChanged = 50
+
+// CaptureGitContext reads the repository enclosing dir, leaving out
+// changes under exclude (the crumbs directory). Returns nil outside a git
+// repository; missing pieces (no remote, no commits yet) are left empty.
+func CaptureGitContext(dir, exclude string) *GitContext {
+	top, err := git(dir, nil, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil
+	}
+
+	ctx := &GitContext{}
+	ctx.Commit, _ = git(top, nil, "rev-parse", "--verify", "--quiet", "HEAD")
+	ctx.Branch, _ = git(top, nil, "symbolic-ref", "--quiet", "--short", "HEAD")
+	if ctx.Remote, err = git(top, nil, "remote", "get-url", "origin"); err != nil {
+		if remotes, _ := git(top, nil, "remote"); remotes != "" {
+			ctx.Remote, _ = git(top, nil, "remote", "get-url", strings.Fields(remotes)[0])
+		}
+	}
+	ctx.Remote = stripCredentials(ctx.Remote)
+
+	var skip string
+	if exclude != "" {
+		if rel, err := relativePaths(top, []string{exclude}); err == nil && rel[0] != "." {
+			skip = filepath.ToSlash(rel[0]) + "/"
+		}
+	}
+
+	// porcelain v2 lines don't start with a space, which git() would trim
+	// untracked directories are listed once rather than file by file
+	status, _ := git(top, nil, "status", "--porcelain=v2", "--untracked-files=normal")
+	count := 0
+	for _, line := range strings.Split(status, "\n") {
+		path := changedPath(line)
+		if path == "" || (skip != "" && strings.HasPrefix(path, skip)) {
+			continue
+		}
+		if count++; count <= maxChanged {
+			ctx.Changed = append(ctx.Changed, path)
+		}
+	}
+	if count > maxChanged {
+		ctx.ChangedCount = count
+	}
+	return ctx
+}
+
+// stripCredentials drops the user info (often a token) from http(s) and
+// ssh remote URLs so it isn't written into shared crumbs; a URL that
+// can't be parsed is dropped rather than risk leaking one
+func stripCredentials(remote string) string {
+	if !strings.Contains(remote, "://") {
+		return remote // a path or scp-like user@host:path, no secret
+	}
+	u, err := url.Parse(remote)
+	if err != nil {
+		return ""
+	}
+	if u.User == nil {
+		return remote
+	}
+	switch u.Scheme {
+	case "http", "https", "ssh", "git+ssh", "ssh+git":
+		u.User = nil
+		return u.String()
+	}
+	return remote
+}
+
+// changedPath extracts the path from a `git status --porcelain=v2` line
+func changedPath(line string) string {
+	var path string
+	switch {
+	case strings.HasPrefix(line, "1 "):
+		path = afterFields(line, 8)
+	case strings.HasPrefix(line, "2 "):
+		// renames end in "path<TAB>origPath"
+		path, _, _ = strings.Cut(afterFields(line, 9), "\t")
+	case strings.HasPrefix(line, "u "):
+		path = afterFields(line, 10)
+	case strings.HasPrefix(line, "? "):
+		path = line[2:]
+	}
+	return strings.Trim(path, `"`)
+}
+
+// afterFields returns everything after the first n space-separated fields
+func afterFields(line string, n int) string {
+	parts := strings.SplitN(line, " ", n+1)
+	if len(parts) <= n {
+		return ""
+	}
+	return parts[n]
+}
+
+// ShortCommit returns the abbreviated commit SHA
+func (g *GitContext) ShortCommit() string {
+	if len(g.Commit) > 7 {
+		return g.Commit[:7]
+	}
+	return g.Commit
+}
+
+var scpRemote = regexp.MustCompile(`^(?:[\w.-]+@)?([\w.-]+):(.+)$`)
+
+// CommitURL links to the commit on the remote's web UI (GitHub, GitLab,
+// Bitbucket and lookalikes), or "" when the remote isn't a known form.
+func (g *GitContext) CommitURL() string {
+	if g == nil || g.Commit == "" || g.Remote == "" {
+		return ""
+	}
+
+	remote := strings.TrimSuffix(strings.TrimSuffix(g.Remote, "/"), ".git")
+	var host, path string
+	switch {
+	case strings.HasPrefix(remote, "https://"), strings.HasPrefix(remote, "http://"), strings.HasPrefix(remote, "ssh://"):
+		_, rest, _ := strings.Cut(remote, "://")
+		host, path, _ = strings.Cut(rest, "/")
+		if _, h, ok := strings.Cut(host, "@"); ok {
+			host = h // drop user (and any token)
+		}
+		if strings.HasPrefix(remote, "ssh://") {
+			host, _, _ = strings.Cut(host, ":") // drop the ssh port
+		}
+	case scpRemote.MatchString(remote):
+		m := scpRemote.FindStringSubmatch(remote)
+		host, path = m[1], m[2]
+	default:
+		return ""
+	}
+	if host == "" || path == "" {
+		return ""
+	}
+
+	base := "https://" + host + "/" + strings.TrimPrefix(path, "/")
+	switch {
+	case strings.Contains(host, "gitlab"):
+		return base + "/-/commit/" + g.Commit
+	case strings.Contains(host, "bitbucket"):
+		return base + "/commits/" + g.Commit
+	default:
+		return base + "/commit/" + g.Commit
+	}
+}
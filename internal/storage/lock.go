@@ -0,0 +1,132 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// LockFileName is the advisory lock file kept in a crumbs directory
+const LockFileName = ".crumb.lock"
+
+// LockTimeout is how long LockDir waits for another crumb process
+var LockTimeout = 10 * time.Second
+
+// lockRetryInterval is how often a held lock is retried
+const lockRetryInterval = 50 * time.Millisecond
+
+// errLockHeld is returned by tryLock when another process holds the lock
+var errLockHeld = errors.New("lock held")
+
+// DirLock is an advisory lock on a crumbs directory, held while files in it
+// are written so concurrent crumb processes don't race.
+type DirLock struct {
+	f *os.File
+}
+
+// LockDir takes the directory's lock, waiting up to LockTimeout for
+// another crumb process to release it.
+func LockDir(dir string) (*DirLock, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	// the lock file is shared by everyone writing to the directory, and
+	// flock works on read-only descriptors if another user created it
+	path := filepath.Join(dir, LockFileName)
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0666)
+	if err == nil {
+		// first lock in this checkout
+		ignoreLockFile(path)
+	} else if os.IsExist(err) {
+		f, err = os.OpenFile(path, os.O_RDWR, 0666)
+	}
+	if os.IsPermission(err) {
+		f, err = os.Open(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock file: %w", err)
+	}
+
+	deadline := time.Now().Add(LockTimeout)
+	for {
+		err := tryLock(f)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, errLockHeld) {
+			f.Close()
+			return nil, fmt.Errorf("failed to lock %s: %w", dir, err)
+		}
+		if time.Now().After(deadline) {
+			f.Close()
+			return nil, fmt.Errorf("%s is locked by another crumb process%s; try again once it finishes", dir, lockOwner(path))
+		}
+		time.Sleep(lockRetryInterval)
+	}
+
+	// record the holder for the error above; best effort
+	if f.Truncate(0) == nil {
+		f.WriteAt([]byte(fmt.Sprintf("%d\n", os.Getpid())), 0)
+	}
+	return &DirLock{f: f}, nil
+}
+
+// ignoreLockFile keeps a newly created lock file out of commits by adding
+// it to the repository's info/exclude, unless a .gitignore already covers
+// it. This is local to the checkout, so nothing needs committing; best
+// effort.
+func ignoreLockFile(path string) {
+	dir := filepath.Dir(path)
+	if _, err := git(dir, nil, "check-ignore", "-q", path); err == nil {
+		return
+	}
+	exclude, err := git(dir, nil, "rev-parse", "--git-path", "info/exclude")
+	if err != nil {
+		return // not in a repository
+	}
+	if !filepath.IsAbs(exclude) {
+		exclude = filepath.Join(dir, exclude)
+	}
+
+	data, err := os.ReadFile(exclude)
+	if err != nil && !os.IsNotExist(err) {
+		return
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.TrimSpace(line) == LockFileName {
+			return
+		}
+	}
+	if len(data) > 0 && !strings.HasSuffix(string(data), "\n") {
+		data = append(data, '\n')
+	}
+	data = append(data, LockFileName+"\n"...)
+	if os.MkdirAll(filepath.Dir(exclude), 0755) == nil {
+		os.WriteFile(exclude, data, 0644)
+	}
+}
+
+// Unlock releases the lock
+func (l *DirLock) Unlock() error {
+	err := unlock(l.f)
+	if cerr := l.f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// lockOwner describes the process recorded in the lock file, if any
+func lockOwner(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	if pid := strings.TrimSpace(string(data)); pid != "" {
+		return fmt.Sprintf(" (pid %s)", pid)
+	}
+	return ""
+}
@@ -0,0 +1,64 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLockDirWaitsForHolder(t *testing.T) {
+	useTempCache(t)
+	dir := t.TempDir()
+
+	timeout := LockTimeout
+	LockTimeout = 200 * time.Millisecond
+	t.Cleanup(func() { LockTimeout = timeout })
+
+	lock, err := LockDir(dir)
+	if err != nil {
+		t.Fatalf("LockDir failed: %v", err)
+	}
+
+	// writers give up with a clear error while the lock is held
+	_, err = NewMarkdownStorage(dir).SaveCrumb(&Crumb{Title: "Blocked", Date: time.Now(), Prompt: "p"})
+	if err == nil || !strings.Contains(err.Error(), "locked by another crumb process (pid") {
+		t.Fatalf("expected lock error, got %v", err)
+	}
+
+	// and succeed once it is released
+	released := make(chan struct{})
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		lock.Unlock()
+		close(released)
+	}()
+	if _, err := NewMarkdownStorage(dir).SaveCrumb(&Crumb{Title: "Waited", Date: time.Now(), Prompt: "p"}); err != nil {
+		t.Fatalf("expected save to wait for the lock, got %v", err)
+	}
+	<-released
+}
+
+func TestLockDirIgnoresLockFile(t *testing.T) {
+	repo, dir := newTestRepo(t)
+
+	for i := 0; i < 2; i++ {
+		lock, err := LockDir(dir)
+		if err != nil {
+			t.Fatalf("LockDir failed: %v", err)
+		}
+		lock.Unlock()
+	}
+
+	if status, _ := git(repo, nil, "status", "--porcelain", "--untracked-files=all"); status != "" {
+		t.Errorf("expected the lock file ignored, got status %q", status)
+	}
+	exclude, err := os.ReadFile(filepath.Join(repo, ".git", "info", "exclude"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(string(exclude), LockFileName); n != 1 {
+		t.Errorf("expected one exclude entry, got %d in %q", n, exclude)
+	}
+}
@@ -1,7 +1,6 @@
 package storage
 
 import (
-	"bufio"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -19,42 +18,201 @@ type PromptMetadata struct {
 }
 
 type MarkdownStorage struct {
-	baseDir string
+	baseDir   string
+	cachePath string // parsed index cache, empty disables caching
+
+	// Overwrite makes Save replace an existing file with the same name
+	// instead of saving under a numbered name (slug-2.md)
+	Overwrite bool
+
+	// Git commits each saved or updated file when set
+	Git *GitCommitter
+
+	// Roster canonicalizes author names returned by List
+	Roster Roster
 }
 
 func NewMarkdownStorage(baseDir string) *MarkdownStorage {
 	return &MarkdownStorage{
-		baseDir: baseDir,
+		baseDir:   baseDir,
+		cachePath: defaultCachePath(baseDir),
 	}
 }
 
-// Save writes markdown content to a file in the base directory.
-// Returns the full filepath on success or an error.
+// Dir returns the directory crumbs are stored in
+func (m *MarkdownStorage) Dir() string {
+	return m.baseDir
+}
+
+// Save writes markdown content to a file in the base directory. An
+// existing file is never replaced unless Overwrite is set; the content is
+// saved as name-2.md, name-3.md, ... instead. Writes are atomic, so an
+// interrupted save can't leave a truncated file.
+// Returns the full filepath on success or an error; a *CommitError means
+// the file was saved but committing it failed.
 func (m *MarkdownStorage) Save(filename string, content string) (string, error) {
-	if err := os.MkdirAll(m.baseDir, 0755); err != nil {
-		return "", fmt.Errorf("failed to create directory: %w", err)
+	return m.save(filename, content, CommitData{Title: strings.TrimSuffix(filename, ".md"), Action: "add"})
+}
+
+func (m *MarkdownStorage) save(filename, content string, commit CommitData) (string, error) {
+	lock, err := LockDir(m.baseDir)
+	if err != nil {
+		return "", err
 	}
+	defer lock.Unlock()
 
 	fullPath := filepath.Join(m.baseDir, filename)
-	if err := os.WriteFile(fullPath, []byte(content), 0644); err != nil {
+	if m.Overwrite {
+		err = writeFileAtomic(fullPath, []byte(content), 0644)
+	} else {
+		fullPath, err = writeFileExclusive(m.baseDir, filename, []byte(content), 0644)
+	}
+	if err != nil {
 		return "", fmt.Errorf("failed to write file: %w", err)
 	}
 
-	return fullPath, nil
+	// commit while holding the lock so concurrent saves don't race on git's index
+	return fullPath, m.Git.Commit(commit, fullPath)
 }
 
-// SaveWithMetadata is the legacy method for saving with structured metadata
-func (m *MarkdownStorage) SaveWithMetadata(metadata PromptMetadata, content string) error {
-	if err := os.MkdirAll(m.baseDir, 0755); err != nil {
-		return fmt.Errorf("failed to create prompts directory: %w", err)
+// SaveCrumb marshals the crumb and writes it to a YYYY-MM-DD-slug.md file
+// (numbered if that name is taken, see Save).
+// Returns the full filepath on success or an error.
+func (m *MarkdownStorage) SaveCrumb(c *Crumb) (string, error) {
+	content, err := c.Marshal()
+	if err != nil {
+		return "", err
+	}
+
+	path, err := m.save(GenerateFilename(c.Title, c.Date), string(content), CommitData{Title: c.Title, Action: "add"})
+	if path != "" {
+		c.Path = path
 	}
+	return path, err
+}
 
-	filename := m.generateFilename(metadata.Title)
-	filepath := filepath.Join(m.baseDir, filename)
+// Update rewrites an existing crumb in place, stamping the updated time.
+// The file is renamed only when the title slug changes (numbered if the
+// new name is taken); the original date keeps the filename's date prefix.
+// Returns the (possibly new) filepath.
+func (m *MarkdownStorage) Update(c *Crumb) (string, error) {
+	if c.Path == "" {
+		return "", fmt.Errorf("crumb has no file to update")
+	}
 
-	markdown := m.formatMarkdown(metadata, content)
+	c.Updated = GetTimestamp()
+	content, err := c.Marshal()
+	if err != nil {
+		return "", err
+	}
 
-	if err := os.WriteFile(filepath, []byte(markdown), 0644); err != nil {
+	lock, err := LockDir(filepath.Dir(c.Path))
+	if err != nil {
+		return "", err
+	}
+	defer lock.Unlock()
+
+	// the file keeps whatever name it has (numbered, hand-named, without a
+	// date) unless the title's slug changes, so links to it don't break
+	oldPath := c.Path
+	newPath := oldPath
+	if titleChanged(c) {
+		newPath, err = writeFileExclusive(filepath.Dir(oldPath), updatedFilename(c), content, 0644)
+	} else {
+		err = writeFileAtomic(oldPath, content, 0644)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to write file: %w", err)
+	}
+	if newPath != oldPath {
+		if err := os.Remove(oldPath); err != nil {
+			return "", fmt.Errorf("failed to remove old file: %w", err)
+		}
+	}
+
+	c.Path = newPath
+	paths := []string{newPath}
+	if newPath != oldPath {
+		paths = append(paths, oldPath)
+	}
+	return newPath, m.Git.Commit(CommitData{Title: c.Title, Action: "update"}, paths...)
+}
+
+// titleChanged reports whether c's title slug differs from the one saved
+// in its file
+func titleChanged(c *Crumb) bool {
+	saved, err := ReadCrumb(c.Path)
+	if err != nil {
+		return false
+	}
+	return Slugify(saved.Title) != Slugify(c.Title)
+}
+
+// updatedFilename names a renamed crumb, leaving out the date prefix when
+// the crumb has no date
+func updatedFilename(c *Crumb) string {
+	if c.Date.IsZero() {
+		return Slugify(c.Title) + ".md"
+	}
+	return GenerateFilename(c.Title, c.Date)
+}
+
+// Delete removes a crumb file
+func (m *MarkdownStorage) Delete(path string) error {
+	lock, err := LockDir(filepath.Dir(path))
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
+	if err := os.Remove(path); err != nil {
+		return fmt.Errorf("failed to delete crumb: %w", err)
+	}
+	return nil
+}
+
+// List parses every crumb in the base directory, reusing the on-disk index
+// cache for files that haven't changed. Files that can't be parsed are
+// skipped, matching how tag suggestions treat them. Authors are mapped
+// through the Roster.
+func (m *MarkdownStorage) List() ([]*Crumb, error) {
+	entries, err := os.ReadDir(m.baseDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []*Crumb{}, nil
+		}
+		return nil, fmt.Errorf("failed to read directory: %w", err)
+	}
+
+	cache := loadCache(m.cachePath, m.baseDir)
+	if cache.refresh(m.baseDir, entries) && m.cachePath != "" {
+		// a stale cache only costs a re-parse next time
+		_ = cache.save(m.cachePath)
+	}
+
+	crumbs := make([]*Crumb, 0, len(entries))
+	for _, entry := range entries {
+		cached := cache.Entries[entry.Name()]
+		if cached == nil || cached.Crumb == nil {
+			continue
+		}
+		cached.Crumb.Path = filepath.Join(m.baseDir, entry.Name())
+		crumbs = append(crumbs, cached.Crumb)
+	}
+	m.Roster.Apply(crumbs)
+
+	return crumbs, nil
+}
+
+// isCrumbFile reports whether a directory entry looks like a crumb
+func isCrumbFile(entry os.DirEntry) bool {
+	return !entry.IsDir() && strings.HasSuffix(entry.Name(), ".md") && entry.Name() != "README.md"
+}
+
+// SaveWithMetadata is the legacy method for saving with structured metadata
+func (m *MarkdownStorage) SaveWithMetadata(metadata PromptMetadata, content string) error {
+	markdown := m.formatMarkdown(metadata, content)
+	if _, err := m.Save(m.generateFilename(metadata.Title), markdown); err != nil {
 		return fmt.Errorf("failed to write prompt file: %w", err)
 	}
 
@@ -113,18 +271,13 @@ func (m *MarkdownStorage) formatMarkdown(metadata PromptMetadata, content string
 func (m *MarkdownStorage) GetFrequentTags(limit int) []string {
 	tagCounts := make(map[string]int)
 
-	entries, err := os.ReadDir(m.baseDir)
+	crumbs, err := m.List()
 	if err != nil {
 		return []string{}
 	}
 
-	for _, entry := range entries {
-		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".md") || entry.Name() == "README.md" {
-			continue
-		}
-
-		tags := m.extractTagsFromFile(filepath.Join(m.baseDir, entry.Name()))
-		for _, tag := range tags {
+	for _, c := range crumbs {
+		for _, tag := range c.Tags {
 			tagCounts[tag]++
 		}
 	}
@@ -149,71 +302,3 @@ func (m *MarkdownStorage) GetFrequentTags(limit int) []string {
 	}
 	return result
 }
-
-// extractTagsFromFile parses YAML frontmatter to extract tags
-func (m *MarkdownStorage) extractTagsFromFile(path string) []string {
-	file, err := os.Open(path)
-	if err != nil {
-		return []string{}
-	}
-	defer file.Close()
-
-	scanner := bufio.NewScanner(file)
-	inFrontmatter := false
-	inTags := false
-	tags := []string{}
-
-	for scanner.Scan() {
-		line := scanner.Text()
-
-		// detect frontmatter boundaries
-		if line == "---" {
-			if !inFrontmatter {
-				inFrontmatter = true
-				continue
-			} else {
-				break // end of frontmatter
-			}
-		}
-
-		if !inFrontmatter {
-			continue
-		}
-
-		// detect tags section
-		if strings.HasPrefix(line, "tags:") {
-			inTags = true
-			// check for inline tags: tags: [tag1, tag2]
-			rest := strings.TrimPrefix(line, "tags:")
-			rest = strings.TrimSpace(rest)
-			if strings.HasPrefix(rest, "[") && strings.HasSuffix(rest, "]") {
-				// inline array format
-				inner := strings.Trim(rest, "[]")
-				for _, t := range strings.Split(inner, ",") {
-					t = strings.TrimSpace(t)
-					if t != "" {
-						tags = append(tags, t)
-					}
-				}
-				inTags = false
-			}
-			continue
-		}
-
-		// parse tag list items
-		if inTags {
-			if strings.HasPrefix(line, "  - ") {
-				tag := strings.TrimPrefix(line, "  - ")
-				tag = strings.TrimSpace(tag)
-				if tag != "" {
-					tags = append(tags, tag)
-				}
-			} else if !strings.HasPrefix(line, "  ") && !strings.HasPrefix(line, "\t") {
-				// no longer indented, end of tags
-				inTags = false
-			}
-		}
-	}
-
-	return tags
-}
@@ -0,0 +1,168 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestSaveCrumbNumbersCollisions(t *testing.T) {
+	useTempCache(t)
+	dir := t.TempDir()
+	s := NewMarkdownStorage(dir)
+	date := time.Date(2024, 12, 3, 10, 0, 0, 0, time.UTC)
+
+	var paths []string
+	for _, prompt := range []string{"first", "second", "third"} {
+		path, err := s.SaveCrumb(&Crumb{Title: "Fix bug", Date: date, Prompt: prompt})
+		if err != nil {
+			t.Fatalf("SaveCrumb failed: %v", err)
+		}
+		paths = append(paths, filepath.Base(path))
+	}
+
+	expected := "2024-12-03-fix-bug.md,2024-12-03-fix-bug-2.md,2024-12-03-fix-bug-3.md"
+	if strings.Join(paths, ",") != expected {
+		t.Errorf("expected %s, got %v", expected, paths)
+	}
+
+	first, err := ReadCrumb(filepath.Join(dir, paths[0]))
+	if err != nil {
+		t.Fatalf("ReadCrumb failed: %v", err)
+	}
+	if first.Prompt != "first" {
+		t.Errorf("expected first crumb to be kept, got %q", first.Prompt)
+	}
+
+	// no temp files are left behind
+	entries, _ := os.ReadDir(dir)
+	for _, e := range entries {
+		if !isCrumbFile(e) && e.Name() != LockFileName {
+			t.Errorf("unexpected file %s", e.Name())
+		}
+	}
+}
+
+func TestSaveOverwrite(t *testing.T) {
+	useTempCache(t)
+	s := NewMarkdownStorage(t.TempDir())
+	s.Overwrite = true
+	date := time.Date(2024, 12, 3, 10, 0, 0, 0, time.UTC)
+
+	if _, err := s.SaveCrumb(&Crumb{Title: "Fix bug", Date: date, Prompt: "old"}); err != nil {
+		t.Fatalf("SaveCrumb failed: %v", err)
+	}
+	path, err := s.SaveCrumb(&Crumb{Title: "Fix bug", Date: date, Prompt: "new"})
+	if err != nil {
+		t.Fatalf("SaveCrumb failed: %v", err)
+	}
+
+	if filepath.Base(path) != "2024-12-03-fix-bug.md" {
+		t.Errorf("expected the same file, got %s", path)
+	}
+	c, err := ReadCrumb(path)
+	if err != nil {
+		t.Fatalf("ReadCrumb failed: %v", err)
+	}
+	if c.Prompt != "new" {
+		t.Errorf("expected overwritten prompt, got %q", c.Prompt)
+	}
+}
+
+func TestUpdateKeepsNumberedName(t *testing.T) {
+	useTempCache(t)
+	s := NewMarkdownStorage(t.TempDir())
+	date := time.Date(2024, 12, 3, 10, 0, 0, 0, time.UTC)
+
+	s.SaveCrumb(&Crumb{Title: "Fix bug", Date: date, Prompt: "first"})
+	second := &Crumb{Title: "Fix bug", Date: date, Prompt: "second"}
+	path, err := s.SaveCrumb(second)
+	if err != nil {
+		t.Fatalf("SaveCrumb failed: %v", err)
+	}
+
+	second.Prompt = "edited"
+	newPath, err := s.Update(second)
+	if err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if newPath != path {
+		t.Errorf("expected %s to keep its name, got %s", path, newPath)
+	}
+}
+
+func TestUpdateKeepsNameWhenTitleUnchanged(t *testing.T) {
+	useTempCache(t)
+	dir := t.TempDir()
+	s := NewMarkdownStorage(dir)
+
+	// a hand-named file and one without a date
+	files := map[string]string{
+		"my-prompt.md": "---\ntitle: My prompt\ndate: 2024-01-02T10:00:00Z\n---\n\n## Prompt\n\np\n",
+		"undated.md":   "---\ntitle: Undated\n---\n\n## Prompt\n\np\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		c, err := ReadCrumb(path)
+		if err != nil {
+			t.Fatalf("ReadCrumb failed: %v", err)
+		}
+
+		c.Tags = []string{"edited"}
+		newPath, err := s.Update(c)
+		if err != nil {
+			t.Fatalf("Update failed: %v", err)
+		}
+		if newPath != path {
+			t.Errorf("expected %s to keep its name, got %s", name, filepath.Base(newPath))
+		}
+	}
+}
+
+func TestUpdateRenamesUndatedCrumb(t *testing.T) {
+	useTempCache(t)
+	dir := t.TempDir()
+	path := filepath.Join(dir, "undated.md")
+	if err := os.WriteFile(path, []byte("---\ntitle: Undated\n---\n\n## Prompt\n\np\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	c, err := ReadCrumb(path)
+	if err != nil {
+		t.Fatalf("ReadCrumb failed: %v", err)
+	}
+
+	c.Title = "Renamed prompt"
+	newPath, err := NewMarkdownStorage(dir).Update(c)
+	if err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if filepath.Base(newPath) != "renamed-prompt.md" {
+		t.Errorf("expected renamed-prompt.md without a date, got %s", filepath.Base(newPath))
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("expected the old file removed")
+	}
+}
+
+func TestWriteFileExclFallback(t *testing.T) {
+	if !linkUnsupported(&os.LinkError{Op: "link", Err: syscall.EXDEV}) || linkUnsupported(&os.LinkError{Op: "link", Err: syscall.EEXIST}) {
+		t.Error("expected only filesystem errors to fall back from hard links")
+	}
+
+	path := filepath.Join(t.TempDir(), "crumb.md")
+	if err := writeFileExcl(path, []byte("first"), 0644); err != nil {
+		t.Fatalf("writeFileExcl failed: %v", err)
+	}
+	if err := writeFileExcl(path, []byte("second"), 0644); !os.IsExist(err) {
+		t.Errorf("expected an exists error, got %v", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "first" {
+		t.Errorf("expected the existing file kept, got %q", data)
+	}
+}
@@ -0,0 +1,93 @@
+package storage
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Variable is a fill-in placeholder declared in a crumb's frontmatter
+type Variable struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description,omitempty"`
+	Default     string `yaml:"default,omitempty"`
+}
+
+// placeholderPattern matches {{name}}, allowing spaces inside the braces
+var placeholderPattern = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_.-]*)\s*\}\}`)
+
+// Placeholders returns the variable names used in text, in order of first
+// appearance.
+func Placeholders(text string) []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, m := range placeholderPattern.FindAllStringSubmatch(text, -1) {
+		if !seen[m[1]] {
+			seen[m[1]] = true
+			names = append(names, m[1])
+		}
+	}
+	return names
+}
+
+// IsTemplate reports whether the crumb declares or uses any variables
+func (c *Crumb) IsTemplate() bool {
+	return len(c.Variables) > 0 || placeholderPattern.MatchString(c.Prompt)
+}
+
+// TemplateVariables returns the declared variables followed by any
+// placeholders in the prompt that weren't declared.
+func (c *Crumb) TemplateVariables() []Variable {
+	vars := make([]Variable, 0, len(c.Variables))
+	seen := make(map[string]bool)
+	for _, v := range c.Variables {
+		if v.Name == "" || seen[v.Name] {
+			continue
+		}
+		seen[v.Name] = true
+		vars = append(vars, v)
+	}
+
+	for _, name := range Placeholders(c.Prompt) {
+		if !seen[name] {
+			seen[name] = true
+			vars = append(vars, Variable{Name: name})
+		}
+	}
+	return vars
+}
+
+// Render fills the prompt's placeholders from values, falling back to each
+// variable's default. Variables with neither are reported as an error.
+func (c *Crumb) Render(values map[string]string) (string, error) {
+	resolved := make(map[string]string)
+	var missing []string
+	for _, v := range c.TemplateVariables() {
+		value, ok := values[v.Name]
+		if !ok {
+			value, ok = v.Default, v.Default != ""
+		}
+		if !ok {
+			missing = append(missing, v.Name)
+			continue
+		}
+		resolved[v.Name] = value
+	}
+	if len(missing) > 0 {
+		return "", fmt.Errorf("missing value for %s", strings.Join(missing, ", "))
+	}
+
+	return RenderPlaceholders(c.Prompt, resolved), nil
+}
+
+// RenderPlaceholders substitutes {{name}} placeholders, leaving unknown
+// ones untouched.
+func RenderPlaceholders(text string, values map[string]string) string {
+	return placeholderPattern.ReplaceAllStringFunc(text, func(m string) string {
+		name := placeholderPattern.FindStringSubmatch(m)[1]
+		if value, ok := values[name]; ok {
+			return value
+		}
+		return m
+	})
+}
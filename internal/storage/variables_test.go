@@ -0,0 +1,70 @@
+package storage
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTemplateVariablesRoundTrip(t *testing.T) {
+	c := &Crumb{
+		Title: "Review a file",
+		Variables: []Variable{
+			{Name: "file", Description: "path to review"},
+			{Name: "concern", Default: "security"},
+		},
+		Prompt: "Review {{file}} for {{ concern }} issues. Reply in {{language}}.",
+	}
+
+	data, err := c.Marshal()
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if !strings.Contains(string(data), "variables:\n  - name: file\n    description: path to review\n") {
+		t.Errorf("expected variables block in frontmatter, got:\n%s", data)
+	}
+
+	parsed, err := Unmarshal(data)
+	if err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	vars := parsed.TemplateVariables()
+	var names []string
+	for _, v := range vars {
+		names = append(names, v.Name)
+	}
+	if strings.Join(names, ",") != "file,concern,language" {
+		t.Errorf("expected declared then undeclared variables, got %v", names)
+	}
+	if vars[1].Default != "security" {
+		t.Errorf("expected default to survive round trip, got %q", vars[1].Default)
+	}
+}
+
+func TestRender(t *testing.T) {
+	c := &Crumb{
+		Variables: []Variable{{Name: "file"}, {Name: "concern", Default: "security"}},
+		Prompt:    "Review {{file}} for {{ concern }} issues. Keep {{file}} short.",
+	}
+
+	got, err := c.Render(map[string]string{"file": "main.go"})
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if got != "Review main.go for security issues. Keep main.go short." {
+		t.Errorf("unexpected render: %q", got)
+	}
+
+	// explicit values win over defaults, even when empty
+	got, err = c.Render(map[string]string{"file": "a.go", "concern": ""})
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if got != "Review a.go for  issues. Keep a.go short." {
+		t.Errorf("unexpected render: %q", got)
+	}
+
+	if _, err := c.Render(nil); err == nil || !strings.Contains(err.Error(), "file") {
+		t.Errorf("expected missing variable error, got %v", err)
+	}
+}
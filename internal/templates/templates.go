@@ -0,0 +1,117 @@
+// Package templates loads starter templates that pre-fill the capture form.
+package templates
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/adrg/xdg"
+
+	"crumb/internal/config"
+	"crumb/internal/storage"
+)
+
+// RepoDir is the templates directory inside the crumbs directory
+const RepoDir = ".templates"
+
+// Template is a starter crumb: its prompt, tags and tool pre-fill the form.
+// Templates use the crumb file format; the frontmatter is optional.
+type Template struct {
+	Name  string // file name without .md, used with `crumb -T`
+	Path  string
+	Crumb *storage.Crumb
+}
+
+// Dirs returns the template directories in increasing priority: the user's
+// config directory, any configured template_dirs, then the crumbs
+// directory's .templates.
+func Dirs(cfg *config.Config, crumbsDir string) []string {
+	dirs := []string{filepath.Join(xdg.ConfigHome, "crumb", "templates")}
+	for _, dir := range cfg.TemplateDirs {
+		if rest, ok := strings.CutPrefix(dir, "~/"); ok {
+			if home, err := os.UserHomeDir(); err == nil {
+				dir = filepath.Join(home, rest)
+			}
+		}
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(crumbsDir, dir)
+		}
+		dirs = append(dirs, dir)
+	}
+	return append(dirs, filepath.Join(crumbsDir, RepoDir))
+}
+
+// Load reads the .md templates in dirs, sorted by name. A template in a
+// later directory replaces one with the same name in an earlier one.
+func Load(dirs []string) ([]Template, error) {
+	byName := make(map[string]Template)
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read templates directory: %w", err)
+		}
+
+		for _, entry := range entries {
+			if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".md") {
+				continue
+			}
+			path := filepath.Join(dir, entry.Name())
+			c, err := read(path)
+			if err != nil {
+				return nil, err
+			}
+			name := strings.TrimSuffix(entry.Name(), ".md")
+			byName[name] = Template{Name: name, Path: path, Crumb: c}
+		}
+	}
+
+	templates := make([]Template, 0, len(byName))
+	for _, t := range byName {
+		templates = append(templates, t)
+	}
+	sort.Slice(templates, func(i, j int) bool {
+		return templates[i].Name < templates[j].Name
+	})
+	return templates, nil
+}
+
+// read parses a template file. Files without frontmatter or a "## Prompt"
+// heading are used as the prompt text as-is.
+func read(path string) (*storage.Crumb, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template: %w", err)
+	}
+
+	if c, err := storage.Unmarshal(data); err == nil && c.Prompt != "" {
+		c.Path = path
+		return c, nil
+	}
+
+	return &storage.Crumb{
+		Prompt: strings.Trim(string(data), "\r\n"),
+		Path:   path,
+	}, nil
+}
+
+// Find returns the template with the given name
+func Find(templates []Template, name string) (Template, error) {
+	names := make([]string, len(templates))
+	for i, t := range templates {
+		if t.Name == name {
+			return t, nil
+		}
+		names[i] = t.Name
+	}
+
+	if len(names) == 0 {
+		return Template{}, fmt.Errorf("template %q not found (no templates installed)", name)
+	}
+	return Template{}, fmt.Errorf("template %q not found (available: %s)", name, strings.Join(names, ", "))
+}
@@ -0,0 +1,53 @@
+package templates
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeTemplate(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	user := t.TempDir()
+	repo := t.TempDir()
+
+	writeTemplate(t, user, "bugfix.md", "---\ntool: Cursor\ntags: [debugging]\n---\n\n## Prompt\n\nuser bugfix\n")
+	writeTemplate(t, user, "review.md", "Review this diff:\n")
+	writeTemplate(t, user, "notes.txt", "ignored")
+	writeTemplate(t, repo, "bugfix.md", "---\ntool: Aider\n---\n\n## Prompt\n\nrepo bugfix\n")
+
+	templates, err := Load([]string{user, repo, filepath.Join(repo, "missing")})
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(templates) != 2 {
+		t.Fatalf("expected 2 templates, got %d", len(templates))
+	}
+
+	bugfix, err := Find(templates, "bugfix")
+	if err != nil {
+		t.Fatalf("Find failed: %v", err)
+	}
+	if bugfix.Crumb.Prompt != "repo bugfix" || bugfix.Crumb.Tool != "Aider" {
+		t.Errorf("expected the repo template to win, got %q/%q", bugfix.Crumb.Prompt, bugfix.Crumb.Tool)
+	}
+
+	review, _ := Find(templates, "review")
+	if review.Crumb.Prompt != "Review this diff:" {
+		t.Errorf("expected plain text template as prompt, got %q", review.Crumb.Prompt)
+	}
+
+	if _, err := Find(templates, "nope"); err == nil || !strings.Contains(err.Error(), "bugfix, review") {
+		t.Errorf("expected error listing templates, got %v", err)
+	}
+}
@@ -1,6 +1,7 @@
 package tui
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -13,6 +14,7 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	"crumb/internal/config"
 	"crumb/internal/storage"
+	"crumb/internal/templates"
 	"crumb/internal/tui/components"
 )
 
@@ -71,12 +73,28 @@ type Model struct {
 
 	config   *config.Config
 	storage  *storage.MarkdownStorage
-	stayOpen bool // --stay flag
+	stayOpen bool           // --stay flag
+	editing  *storage.Crumb // crumb being edited, nil when capturing a new one
+	embedded bool           // running inside the browser, which regains control on exit
+
+	templates       []templates.Template
+	templatesErr    error
+	templatePicker  components.Dropdown
+	pickingTemplate bool
+	variables       []storage.Variable // declared by the applied template
+
+	draftPath      string // autosave file, empty when editing an existing crumb
+	baseline       draft  // form contents when opened or last saved
+	autosaved      draft  // form contents at the last autosave
+	recovered      *draft // draft awaiting the "recover?" answer
+	confirmDiscard bool
+
 	width    int
 	height   int
 }
 
-func New(cfg *config.Config, tool, initialTitle string, stay bool) Model {
+// New returns the capture form saving into store's directory
+func New(cfg *config.Config, store *storage.MarkdownStorage, tool, initialTitle string, stay bool) Model {
 	// initialize prompt textarea (focused first - most important field)
 	promptTA := textarea.New()
 	promptTA.Placeholder = "Enter your prompt here..."
@@ -93,14 +111,8 @@ func New(cfg *config.Config, tool, initialTitle string, stay bool) Model {
 		titleInput.SetValue(initialTitle)
 	}
 
-	// initialize storage with output directory from config
-	// resolve relative path to absolute path based on cwd
-	cwd, _ := os.Getwd()
-	outputDir := cfg.OutputDir
-	if !filepath.IsAbs(outputDir) {
-		outputDir = filepath.Join(cwd, outputDir)
-	}
-	markdownStorage := storage.NewMarkdownStorage(outputDir)
+	outputDir := store.Dir()
+	markdownStorage := store
 
 	// build tag suggestions: config favorites + frequent tags from existing crumbs
 	tagSuggestions := mergeTagSuggestions(cfg.FavoriteTags, markdownStorage.GetFrequentTags(10))
@@ -126,7 +138,14 @@ func New(cfg *config.Config, tool, initialTitle string, stay bool) Model {
 	}
 	toolDropdown := components.NewDropdown(allTools, defaultIdx, tool)
 
-	return Model{
+	// starter templates from the user's config and the crumbs directory
+	starters, templatesErr := templates.Load(templates.Dirs(cfg, outputDir))
+	names := make([]string, len(starters))
+	for i, t := range starters {
+		names[i] = t.Name
+	}
+
+	m := Model{
 		prompt:     promptTA,
 		title:      titleInput,
 		tags:       tagsInput,
@@ -140,17 +159,107 @@ func New(cfg *config.Config, tool, initialTitle string, stay bool) Model {
 		config:     cfg,
 		storage:    markdownStorage,
 		stayOpen:   stay,
+
+		templates:      starters,
+		templatesErr:   templatesErr,
+		templatePicker: components.NewDropdown(names, 0, ""),
+
 		width:      80,
 		height:     24,
 	}
+
+	// offer to recover a capture that was never saved
+	m.draftPath = draftPath(outputDir)
+	m.baseline = m.snapshot()
+	m.autosaved = m.baseline
+	if d, err := loadDraft(m.draftPath); err == nil && d != nil {
+		m.recovered = d
+	}
+	return m
+}
+
+// NewEdit returns a Model pre-filled with an existing crumb. Saving rewrites
+// the crumb's file instead of creating a new one.
+func NewEdit(cfg *config.Config, store *storage.MarkdownStorage, crumb *storage.Crumb) Model {
+	m := New(cfg, store, crumb.Tool, crumb.Title, false)
+	m.selectTool(crumb.Tool)
+
+	// never truncate existing content that exceeds the capture limits
+	m.prompt.CharLimit = max(m.prompt.CharLimit, len([]rune(crumb.Prompt)))
+	m.output.CharLimit = max(m.output.CharLimit, len([]rune(crumb.Output)))
+
+	m.prompt.SetValue(crumb.Prompt)
+	m.prompt.CursorStart()
+	m.output.SetValue(crumb.Output)
+	m.tags.SetTags(crumb.Tags)
+	m.editing = crumb
+
+	// the crumb file itself is the saved copy, so edits aren't autosaved
+	m.draftPath = ""
+	m.recovered = nil
+	m.baseline = m.snapshot()
+
+	return m
+}
+
+// selectTool selects tool in the dropdown, adding it if it isn't configured
+// (e.g. imported crumbs or templates for another team's tools)
+func (m *Model) selectTool(tool string) {
+	if tool == "" || tool == m.toolSelect.Selected() {
+		return
+	}
+
+	allTools := config.GetAllTools(m.config)
+	for i, t := range allTools {
+		if t == tool {
+			m.toolSelect = components.NewDropdown(allTools, i, m.config.DefaultTool)
+			return
+		}
+	}
+
+	allTools = append(append([]string{}, allTools...), tool)
+	m.toolSelect = components.NewDropdown(allTools, len(allTools)-1, m.config.DefaultTool)
+}
+
+// ApplyTemplate pre-fills the form from the named starter template
+func (m *Model) ApplyTemplate(name string) error {
+	if m.templatesErr != nil {
+		return m.templatesErr
+	}
+	t, err := templates.Find(m.templates, name)
+	if err != nil {
+		return err
+	}
+	m.applyTemplate(t)
+	m.baseline = m.snapshot()
+	m.autosaved = m.baseline
+	return nil
+}
+
+// applyTemplate replaces the prompt with the template's and adopts its
+// tool and tags
+func (m *Model) applyTemplate(t templates.Template) {
+	m.prompt.CharLimit = max(m.prompt.CharLimit, len([]rune(t.Crumb.Prompt)))
+	m.prompt.SetValue(t.Crumb.Prompt)
+	m.prompt.CursorStart()
+	m.selectTool(t.Crumb.Tool)
+	if len(t.Crumb.Tags) > 0 {
+		m.tags.SetTags(t.Crumb.Tags)
+	}
+	m.variables = t.Crumb.Variables
+	m.setFocus(0)
 }
 
 func (m Model) Init() tea.Cmd {
-	return textarea.Blink
+	if m.draftPath == "" {
+		return textarea.Blink
+	}
+	return tea.Batch(textarea.Blink, autosaveTick())
 }
 
 type saveSuccessMsg struct {
 	filename string
+	warning  string // the save worked but something after it didn't (e.g. git commit)
 }
 
 type saveErrorMsg struct {
@@ -159,6 +268,18 @@ type saveErrorMsg struct {
 
 type quitAfterDelayMsg struct{}
 
+// formClosedMsg tells the browser an embedded form was saved or cancelled
+type formClosedMsg struct{}
+
+// exit quits the program, or hands control back to the browser when the
+// form is embedded in it
+func (m Model) exit() tea.Cmd {
+	if m.embedded {
+		return func() tea.Msg { return formClosedMsg{} }
+	}
+	return tea.Quit
+}
+
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmds []tea.Cmd
 
@@ -167,6 +288,12 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.showToast = true
 		m.isError = false
 		m.toastMsg = "Saved!"
+		delay := 500 * time.Millisecond
+		if msg.warning != "" {
+			m.isError = true
+			m.toastMsg = msg.warning
+			delay = 2 * time.Second
+		}
 
 		if m.stayOpen {
 			// clear fields and return focus to prompt
@@ -175,7 +302,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 		// exit after brief delay
-		return m, tea.Tick(500*time.Millisecond, func(t time.Time) tea.Msg {
+		return m, tea.Tick(delay, func(t time.Time) tea.Msg {
 			return quitAfterDelayMsg{}
 		})
 
@@ -186,7 +313,14 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, HideToastAfter(3 * time.Second)
 
 	case quitAfterDelayMsg:
-		return m, tea.Quit
+		return m, m.exit()
+
+	case editorClosedMsg:
+		return m, m.loadFromEditor(msg)
+
+	case autosaveMsg:
+		m.autosave()
+		return m, autosaveTick()
 
 	case ToastHideMsg:
 		m.showToast = false
@@ -210,13 +344,29 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 
+		if m.recovered != nil {
+			return m.updateRecover(msg)
+		}
+		if m.confirmDiscard {
+			return m.updateConfirmDiscard(msg)
+		}
+		if m.pickingTemplate {
+			return m.updateTemplatePicker(msg)
+		}
+
 		// global key bindings
 		switch msg.String() {
 		case "ctrl+c", "ctrl+d":
+			// keep the draft so the next launch can recover it
+			m.autosave()
 			return m, tea.Quit
 
 		case "esc":
-			return m, tea.Quit
+			if m.dirty() {
+				m.confirmDiscard = true
+				return m, nil
+			}
+			return m, m.exit()
 
 		case "?":
 			m.showHelp = true
@@ -229,6 +379,12 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.setFocus(3) // tool selector
 			return m, nil
 
+		case "ctrl+o":
+			return m, m.openTemplatePicker()
+
+		case "ctrl+e":
+			return m, m.openInEditor()
+
 		case "tab":
 			m.focusNext()
 			return m, nil
@@ -270,15 +426,141 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, tea.Batch(cmds...)
 }
 
+// editorClosedMsg carries the temp file edited for a form field
+type editorClosedMsg struct {
+	field int // 0=prompt, 1=output
+	path  string
+	err   error
+}
+
+// openInEditor suspends the TUI and opens the focused prompt or output
+// (the prompt when another field is focused) in $EDITOR
+func (m *Model) openInEditor() tea.Cmd {
+	field, content := 0, m.prompt.Value()
+	if m.focusIndex == 1 {
+		field, content = 1, m.output.Value()
+	}
+
+	f, err := os.CreateTemp("", "crumb-*.md")
+	if err == nil {
+		_, err = f.WriteString(content)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+	}
+	if err != nil {
+		m.showToast = true
+		m.isError = true
+		m.toastMsg = "Error: failed to create temp file: " + err.Error()
+		return HideToastAfter(3 * time.Second)
+	}
+
+	path := f.Name()
+	return tea.ExecProcess(config.EditorCommand(path), func(err error) tea.Msg {
+		return editorClosedMsg{field: field, path: path, err: err}
+	})
+}
+
+// loadFromEditor copies the edited temp file back into its field
+func (m *Model) loadFromEditor(msg editorClosedMsg) tea.Cmd {
+	defer os.Remove(msg.path)
+
+	data, err := os.ReadFile(msg.path)
+	if msg.err != nil {
+		err = fmt.Errorf("editor failed: %w", msg.err)
+	}
+	if err != nil {
+		m.showToast = true
+		m.isError = true
+		m.toastMsg = "Error: " + err.Error()
+		return HideToastAfter(3 * time.Second)
+	}
+
+	// editors usually add a final newline the textarea didn't have
+	content := strings.TrimSuffix(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+	if msg.field == 1 {
+		m.output.CharLimit = max(m.output.CharLimit, len([]rune(content)))
+		m.output.SetValue(content)
+	} else {
+		m.prompt.CharLimit = max(m.prompt.CharLimit, len([]rune(content)))
+		m.prompt.SetValue(content)
+	}
+	m.setFocus(msg.field)
+	return nil
+}
+
+// openTemplatePicker shows the list of starter templates
+func (m *Model) openTemplatePicker() tea.Cmd {
+	if m.templatesErr != nil {
+		m.showToast = true
+		m.isError = true
+		m.toastMsg = "Error: " + m.templatesErr.Error()
+		return HideToastAfter(3 * time.Second)
+	}
+	if len(m.templates) == 0 {
+		m.showToast = true
+		m.isError = true
+		m.toastMsg = "No templates in ~/.config/crumb/templates or " + filepath.Join(displayDir(m.storage.Dir()), templates.RepoDir)
+		return HideToastAfter(3 * time.Second)
+	}
+
+	m.pickingTemplate = true
+	m.templatePicker.Focus()
+	m.templatePicker.Open()
+	return nil
+}
+
+func (m Model) updateTemplatePicker(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+
+	case "esc", "ctrl+o":
+		m.pickingTemplate = false
+		m.templatePicker.Close()
+		return m, nil
+
+	case "enter":
+		m.pickingTemplate = false
+		m.templatePicker.Close()
+		t, err := templates.Find(m.templates, m.templatePicker.Selected())
+		if err != nil {
+			return m, nil
+		}
+		m.applyTemplate(t)
+		m.showToast = true
+		m.isError = false
+		m.toastMsg = "Loaded template " + t.Name
+		return m, HideToastAfter(2 * time.Second)
+	}
+
+	var cmd tea.Cmd
+	m.templatePicker, cmd = m.templatePicker.Update(msg)
+	return m, cmd
+}
+
 func (m Model) View() string {
 	var b strings.Builder
 
 	// header
 	b.WriteString(titleStyle.Render("crumb"))
 	b.WriteString("  ")
-	b.WriteString(helpStyle.Render(fmt.Sprintf("→ %s/", m.config.OutputDir)))
+	if m.editing != nil {
+		b.WriteString(helpStyle.Render(fmt.Sprintf("editing %s", filepath.Base(m.editing.Path))))
+	} else {
+		b.WriteString(helpStyle.Render(fmt.Sprintf("→ %s/", displayDir(m.storage.Dir()))))
+	}
 	b.WriteString("\n\n")
 
+	if m.pickingTemplate {
+		b.WriteString(focusedLabelStyle.Render("→ Template:"))
+		b.WriteString(" ")
+		b.WriteString(helpStyle.Render("(type to filter, enter to load, esc to cancel)"))
+		b.WriteString("\n")
+		b.WriteString(m.templatePicker.View())
+		b.WriteString("\n\n")
+	}
+
 	// prompt field (index 0) - first and most important
 	label := labelStyle.Render("Prompt:")
 	if m.focusIndex == 0 {
@@ -330,8 +612,19 @@ func (m Model) View() string {
 	b.WriteString(m.tags.View())
 	b.WriteString("\n\n")
 
-	// help text
-	b.WriteString(helpStyle.Render("Tab: next • Shift+Tab: prev • Ctrl+S: save • ?: help • Esc: cancel"))
+	// help text, replaced by any pending question
+	switch {
+	case m.recovered != nil:
+		b.WriteString(browseConfirmStyle.Render(fmt.Sprintf(
+			"Recover unsaved draft from %s (%d chars)? (Y/n)",
+			m.recovered.SavedAt.Local().Format("Jan 2 15:04"),
+			len([]rune(m.recovered.Prompt))+len([]rune(m.recovered.Output)),
+		)))
+	case m.confirmDiscard:
+		b.WriteString(browseConfirmStyle.Render("Discard unsaved changes? (y/N)"))
+	default:
+		b.WriteString(helpStyle.Render("Tab: next • Shift+Tab: prev • Ctrl+S: save • Ctrl+E: $EDITOR • Ctrl+O: template • ?: help • Esc: cancel"))
+	}
 
 	// use full width and height
 	contentStyle := lipgloss.NewStyle().
@@ -364,12 +657,14 @@ func (m Model) renderHelp() string {
 	b.WriteString("\n")
 	b.WriteString("  Tab / Shift+Tab     Navigate between fields\n")
 	b.WriteString("  / or Ctrl+T         Focus tool selector\n")
+	b.WriteString("  Ctrl+O              Start from a template\n")
 	b.WriteString("\n")
 
 	b.WriteString(labelStyle.Render("Editing:"))
 	b.WriteString("\n")
 	b.WriteString("  Enter               Add tag (in tags field)\n")
 	b.WriteString("  Backspace           Remove last tag (in tags field)\n")
+	b.WriteString("  Ctrl+E              Edit prompt/output in $EDITOR\n")
 	b.WriteString("  Ctrl+S              Save and exit\n")
 	b.WriteString("\n")
 
@@ -464,69 +759,104 @@ func (m *Model) saveAndExit() tea.Cmd {
 		return HideToastAfter(2 * time.Second)
 	}
 
-	// use title if provided, otherwise auto-generate from prompt
-	title := strings.TrimSpace(m.title.Value())
-	if title == "" {
-		title = storage.GenerateTitle(m.prompt.Value())
+	if m.editing != nil {
+		return m.saveEdit()
 	}
 
-	// generate filename
-	timestamp := storage.GetTimestamp()
-	filename := storage.GenerateFilename(title, timestamp)
-
-	// get git author
-	author := storage.GetGitAuthor()
-	if author == "" {
-		author = "Unknown"
+	// title is auto-generated from the prompt when left empty
+	crumb := storage.NewCrumb(
+		m.prompt.Value(),
+		m.output.Value(),
+		m.title.Value(),
+		m.toolSelect.Selected(),
+		m.tags.Tags(),
+		m.config.Identity(),
+	)
+
+	if m.config.Git.ContextEnabled() {
+		crumb.Git = storage.CaptureGitContext(".", m.storage.Dir())
 	}
 
-	// build markdown content
-	var content strings.Builder
-	content.WriteString("---\n")
-	content.WriteString(fmt.Sprintf("title: %s\n", title))
-	content.WriteString(fmt.Sprintf("date: %s\n", timestamp.Format(time.RFC3339)))
-	content.WriteString(fmt.Sprintf("author: %s\n", author))
-	content.WriteString(fmt.Sprintf("tool: %s\n", m.toolSelect.Selected()))
-
-	if len(m.tags.Tags()) > 0 {
-		content.WriteString("tags:\n")
-		for _, tag := range m.tags.Tags() {
-			content.WriteString(fmt.Sprintf("  - %s\n", tag))
-		}
-	}
-
-	content.WriteString("---\n\n")
-	content.WriteString(fmt.Sprintf("# %s\n\n", title))
-	content.WriteString("## Prompt\n\n")
-	content.WriteString(m.prompt.Value())
-	content.WriteString("\n\n")
-
-	if strings.TrimSpace(m.output.Value()) != "" {
-		content.WriteString("## Output\n\n")
-		content.WriteString(m.output.Value())
-		content.WriteString("\n")
+	// keep a template's variable declarations while its placeholders remain
+	if len(storage.Placeholders(crumb.Prompt)) > 0 {
+		crumb.Variables = m.variables
 	}
 
 	// actually save to file system
-	filepath, err := m.storage.Save(filename, content.String())
+	filepath, err := m.storage.SaveCrumb(crumb)
+	warning, err := commitWarning(err)
 	if err != nil {
 		m.showToast = true
 		m.isError = true
 		m.toastMsg = "Error: " + err.Error()
 		return HideToastAfter(3 * time.Second)
 	}
+	m.markClean()
 
 	// show success message
 	if m.stayOpen {
 		m.showToast = true
-		m.isError = false
+		m.isError = warning != ""
 		m.toastMsg = fmt.Sprintf("Saved: %s", filepath)
+		if warning != "" {
+			m.toastMsg = warning
+		}
 		m.clearFields()
 		return HideToastAfter(2 * time.Second)
 	}
 
 	// return success message which will trigger exit
-	return func() tea.Msg { return saveSuccessMsg{filename: filepath} }
+	return func() tea.Msg { return saveSuccessMsg{filename: filepath, warning: warning} }
+}
+
+// saveEdit rewrites the crumb being edited, keeping its original date,
+// author and any frontmatter keys the form doesn't know about
+func (m *Model) saveEdit() tea.Cmd {
+	crumb := *m.editing
+	crumb.Title = strings.TrimSpace(m.title.Value())
+	if crumb.Title == "" {
+		crumb.Title = storage.GenerateTitle(m.prompt.Value())
+	}
+	crumb.Tool = m.toolSelect.Selected()
+	crumb.Tags = m.tags.Tags()
+	crumb.Prompt = m.prompt.Value()
+	crumb.Output = m.output.Value()
+
+	filepath, err := m.storage.Update(&crumb)
+	warning, err := commitWarning(err)
+	if err != nil {
+		m.showToast = true
+		m.isError = true
+		m.toastMsg = "Error: " + err.Error()
+		return HideToastAfter(3 * time.Second)
+	}
+
+	m.editing = &crumb
+	m.markClean()
+	return func() tea.Msg { return saveSuccessMsg{filename: filepath, warning: warning} }
+}
+
+// commitWarning splits a failed git commit, which still saved the crumb,
+// from errors that mean nothing was saved
+func commitWarning(err error) (string, error) {
+	var commitErr *storage.CommitError
+	if errors.As(err, &commitErr) {
+		return commitErr.Error(), nil
+	}
+	return "", err
+}
+
+// displayDir shows dir relative to the working directory when it's inside it
+func displayDir(dir string) string {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return dir
+	}
+	rel, err := filepath.Rel(cwd, dir)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return dir
+	}
+	return rel
 }
 
 // clearFields resets all input fields to empty state
@@ -537,7 +867,9 @@ func (m *Model) clearFields() {
 	tagSuggestions := mergeTagSuggestions(m.config.FavoriteTags, m.storage.GetFrequentTags(10))
 	m.tags = components.NewTagInput(tagSuggestions)
 	m.output.Reset()
+	m.variables = nil
 	m.setFocus(0)
+	m.markClean()
 }
 
 // mergeTagSuggestions combines config favorites with frequent tags, removing duplicates
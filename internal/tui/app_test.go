@@ -0,0 +1,144 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/adrg/xdg"
+
+	"crumb/internal/config"
+	"crumb/internal/storage"
+)
+
+func TestNewEditSavesInPlace(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	xdg.Reload()
+	t.Cleanup(xdg.Reload)
+
+	dir := t.TempDir()
+	original := &storage.Crumb{
+		Title:  "Original",
+		Date:   time.Date(2024, 12, 3, 0, 0, 0, 0, time.UTC),
+		Author: "Ryan",
+		Tool:   "Imported Tool",
+		Tags:   []string{"go"},
+		Prompt: "prompt",
+		Output: "output",
+	}
+	path, err := storage.NewMarkdownStorage(dir).SaveCrumb(original)
+	if err != nil {
+		t.Fatalf("SaveCrumb failed: %v", err)
+	}
+
+	loaded, err := storage.ReadCrumb(path)
+	if err != nil {
+		t.Fatalf("ReadCrumb failed: %v", err)
+	}
+
+	m := NewEdit(config.DefaultConfig(), storage.NewMarkdownStorage(dir), loaded)
+	if m.prompt.Value() != "prompt" || m.output.Value() != "output" {
+		t.Errorf("expected form pre-filled, got %q/%q", m.prompt.Value(), m.output.Value())
+	}
+	if m.toolSelect.Selected() != "Imported Tool" {
+		t.Errorf("expected unknown tool to stay selected, got %q", m.toolSelect.Selected())
+	}
+
+	m.title.SetValue("Edited")
+	cmd := m.saveAndExit()
+	msg, ok := cmd().(saveSuccessMsg)
+	if !ok {
+		t.Fatalf("expected saveSuccessMsg, got %T (toast: %s)", cmd(), m.toastMsg)
+	}
+
+	if filepath.Base(msg.filename) != "2024-12-03-edited.md" {
+		t.Errorf("expected renamed file, got %s", msg.filename)
+	}
+
+	saved, err := storage.ReadCrumb(msg.filename)
+	if err != nil {
+		t.Fatalf("ReadCrumb failed: %v", err)
+	}
+	if saved.Author != "Ryan" || len(saved.Tags) != 1 || saved.Updated.IsZero() {
+		t.Errorf("unexpected saved crumb %+v", saved)
+	}
+}
+
+func TestApplyTemplate(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	xdg.Reload()
+	t.Cleanup(xdg.Reload)
+
+	cfg := config.DefaultConfig()
+	cfg.OutputDir = t.TempDir()
+	tmpl := "---\ntool: Custom Tool\ntags: [review]\nvariables:\n  - name: file\n---\n\n## Prompt\n\nReview {{file}}\n"
+	if err := os.MkdirAll(filepath.Join(cfg.OutputDir, ".templates"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(cfg.OutputDir, ".templates", "review.md"), []byte(tmpl), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	m := New(cfg, storage.NewMarkdownStorage(cfg.OutputDir), cfg.DefaultTool, "", false)
+	if err := m.ApplyTemplate("missing"); err == nil {
+		t.Error("expected error for unknown template")
+	}
+	if err := m.ApplyTemplate("review"); err != nil {
+		t.Fatalf("ApplyTemplate failed: %v", err)
+	}
+
+	if m.prompt.Value() != "Review {{file}}" {
+		t.Errorf("expected template prompt, got %q", m.prompt.Value())
+	}
+	if m.toolSelect.Selected() != "Custom Tool" {
+		t.Errorf("expected template tool, got %q", m.toolSelect.Selected())
+	}
+	if tags := m.tags.Tags(); len(tags) != 1 || tags[0] != "review" {
+		t.Errorf("expected template tags, got %v", tags)
+	}
+
+	cmd := m.saveAndExit()
+	msg, ok := cmd().(saveSuccessMsg)
+	if !ok {
+		t.Fatalf("expected saveSuccessMsg, got %T", cmd())
+	}
+	saved, err := storage.ReadCrumb(msg.filename)
+	if err != nil {
+		t.Fatalf("ReadCrumb failed: %v", err)
+	}
+	if len(saved.Variables) != 1 || saved.Variables[0].Name != "file" {
+		t.Errorf("expected template variables to be saved, got %v", saved.Variables)
+	}
+}
+
+func TestLoadFromEditor(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	xdg.Reload()
+	t.Cleanup(xdg.Reload)
+
+	cfg := config.DefaultConfig()
+	cfg.OutputDir = t.TempDir()
+	m := New(cfg, storage.NewMarkdownStorage(cfg.OutputDir), cfg.DefaultTool, "", false)
+
+	path := filepath.Join(t.TempDir(), "field.md")
+	if err := os.WriteFile(path, []byte("line one\nline two\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	updated, _ := m.Update(editorClosedMsg{field: 1, path: path})
+	m = updated.(Model)
+	if m.output.Value() != "line one\nline two" {
+		t.Errorf("expected output from editor without trailing newline, got %q", m.output.Value())
+	}
+	if m.focusIndex != 1 {
+		t.Errorf("expected focus on the edited field, got %d", m.focusIndex)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("expected temp file to be removed")
+	}
+}
@@ -0,0 +1,554 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/glamour"
+	"github.com/charmbracelet/lipgloss"
+
+	"crumb/internal/clipboard"
+	"crumb/internal/config"
+	"crumb/internal/search"
+	"crumb/internal/storage"
+)
+
+var (
+	browseSelectedStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color(Peach)).
+				Bold(true)
+
+	browseItemStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color(Text))
+
+	browseMetaStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color(Overlay))
+
+	browsePaneStyle = lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(lipgloss.Color(Surface))
+
+	browseConfirmStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color(Red)).
+				Bold(true)
+)
+
+// Browser is the library browser: a filterable list of crumbs with a
+// rendered preview of the selected one.
+type Browser struct {
+	config  *config.Config
+	storage *storage.MarkdownStorage
+
+	crumbs  []*storage.Crumb // newest first
+	visible []*storage.Crumb // crumbs matching the filter
+	cursor  int
+	offset  int // first visible list row
+
+	filter    textinput.Model
+	filtering bool
+
+	preview    viewport.Model
+	glamStyle  string
+	rendered   map[string]string // preview cache keyed by path
+	renderedAt int               // preview width the cache was rendered for
+
+	confirmDelete bool
+	edit          *Model // embedded edit form, nil when browsing
+
+	showToast bool
+	toastMsg  string
+	isError   bool
+
+	width  int
+	height int
+}
+
+// browserReloadMsg asks the browser to re-read the crumbs directory
+type browserReloadMsg struct{}
+
+// NewBrowser loads every crumb in the store. Call before starting the
+// program so the terminal background can be queried for the preview style.
+func NewBrowser(cfg *config.Config, store *storage.MarkdownStorage) (Browser, error) {
+	filterInput := textinput.New()
+	filterInput.Placeholder = "filter (e.g. flaky tag:testing tool:Cursor)"
+	filterInput.Prompt = "/ "
+
+	glamStyle := "light"
+	if lipgloss.HasDarkBackground() {
+		glamStyle = "dark"
+	}
+
+	b := Browser{
+		config:    cfg,
+		storage:   store,
+		filter:    filterInput,
+		preview:   viewport.New(40, 20),
+		glamStyle: glamStyle,
+		rendered:  make(map[string]string),
+		width:     80,
+		height:    24,
+	}
+	if err := b.reload(); err != nil {
+		return b, err
+	}
+	return b, nil
+}
+
+func (b Browser) Init() tea.Cmd {
+	return nil
+}
+
+// reload re-reads crumbs from disk and re-applies the filter
+func (b *Browser) reload() error {
+	crumbs, err := b.storage.List()
+	if err != nil {
+		return err
+	}
+	if err := storage.SortCrumbs(crumbs, "date", false); err != nil {
+		return err
+	}
+
+	b.crumbs = crumbs
+	b.rendered = make(map[string]string)
+	b.applyFilter()
+	return nil
+}
+
+// applyFilter narrows the list using the search query syntax
+func (b *Browser) applyFilter() {
+	query := search.ParseQuery(b.filter.Value())
+	if query.Empty() {
+		b.visible = b.crumbs
+	} else {
+		results := search.NewIndex(b.crumbs).Search(query)
+		b.visible = make([]*storage.Crumb, len(results))
+		for i, r := range results {
+			b.visible[i] = r.Crumb
+		}
+	}
+
+	b.moveCursor(0)
+}
+
+func (b Browser) selected() *storage.Crumb {
+	if b.cursor < 0 || b.cursor >= len(b.visible) {
+		return nil
+	}
+	return b.visible[b.cursor]
+}
+
+func (b Browser) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	// the embedded edit form owns the screen until it closes
+	if b.edit != nil {
+		switch msg := msg.(type) {
+		case formClosedMsg:
+			b.edit = nil
+			return b, func() tea.Msg { return browserReloadMsg{} }
+		case tea.WindowSizeMsg:
+			b.resize(msg.Width, msg.Height)
+		}
+
+		updated, cmd := b.edit.Update(msg)
+		form := updated.(Model)
+		b.edit = &form
+		return b, cmd
+	}
+
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		b.resize(msg.Width, msg.Height)
+		return b, nil
+
+	case browserReloadMsg:
+		if err := b.reload(); err != nil {
+			return b, b.toast("Error: "+err.Error(), true)
+		}
+		return b, nil
+
+	case saveErrorMsg:
+		return b, b.toast("Error: "+msg.err.Error(), true)
+
+	case ToastHideMsg:
+		b.showToast = false
+		return b, nil
+
+	case tea.KeyMsg:
+		if b.confirmDelete {
+			return b.updateConfirmDelete(msg)
+		}
+		if b.filtering {
+			return b.updateFilter(msg)
+		}
+		return b.updateList(msg)
+	}
+
+	return b, nil
+}
+
+func (b Browser) updateList(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		// first esc clears an active filter
+		if b.filter.Value() != "" {
+			b.filter.SetValue("")
+			b.applyFilter()
+			return b, nil
+		}
+		return b, tea.Quit
+
+	case "ctrl+c", "q":
+		return b, tea.Quit
+
+	case "up", "k":
+		b.moveCursor(-1)
+	case "down", "j":
+		b.moveCursor(1)
+	case "home", "g":
+		b.moveCursor(-len(b.visible))
+	case "end", "G":
+		b.moveCursor(len(b.visible))
+
+	case "pgup", "ctrl+u":
+		b.preview.HalfPageUp()
+	case "pgdown", "ctrl+d":
+		b.preview.HalfPageDown()
+
+	case "/":
+		b.filtering = true
+		b.filter.Focus()
+		return b, textinput.Blink
+
+	case "c", "y":
+		return b, b.copySelected(false)
+	case "C", "Y":
+		return b, b.copySelected(true)
+
+	case "o":
+		return b, b.openSelected()
+
+	case "e":
+		return b, b.editSelected()
+
+	case "d":
+		if b.selected() != nil {
+			b.confirmDelete = true
+		}
+	}
+
+	return b, nil
+}
+
+func (b Browser) updateFilter(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return b, tea.Quit
+	case "esc":
+		b.filter.SetValue("")
+		fallthrough
+	case "enter":
+		b.filtering = false
+		b.filter.Blur()
+		b.applyFilter()
+		return b, nil
+	case "up", "down":
+		b.filtering = false
+		b.filter.Blur()
+		return b.updateList(msg)
+	}
+
+	var cmd tea.Cmd
+	before := b.filter.Value()
+	b.filter, cmd = b.filter.Update(msg)
+	if b.filter.Value() != before {
+		b.cursor = 0
+		b.offset = 0
+		b.applyFilter()
+	}
+	return b, cmd
+}
+
+func (b Browser) updateConfirmDelete(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	b.confirmDelete = false
+
+	c := b.selected()
+	if c == nil || (msg.String() != "y" && msg.String() != "Y") {
+		return b, nil
+	}
+
+	if err := b.storage.Delete(c.Path); err != nil {
+		return b, b.toast("Error: "+err.Error(), true)
+	}
+	if err := b.reload(); err != nil {
+		return b, b.toast("Error: "+err.Error(), true)
+	}
+	return b, b.toast("Deleted "+c.Title, false)
+}
+
+// moveCursor moves the selection, scrolling the list to keep it visible
+func (b *Browser) moveCursor(delta int) {
+	b.cursor = min(max(b.cursor+delta, 0), max(len(b.visible)-1, 0))
+
+	rows := b.listRows()
+	if b.cursor < b.offset {
+		b.offset = b.cursor
+	}
+	if b.cursor >= b.offset+rows {
+		b.offset = b.cursor - rows + 1
+	}
+
+	b.updatePreview()
+}
+
+// copySelected puts the selected crumb's prompt (and optionally its
+// output) on the clipboard
+func (b *Browser) copySelected(withOutput bool) tea.Cmd {
+	c := b.selected()
+	if c == nil {
+		return nil
+	}
+
+	method, err := clipboard.Copy(c.CopyText(withOutput))
+	if err != nil {
+		return b.toast("Error: "+err.Error(), true)
+	}
+
+	what := "prompt"
+	if withOutput {
+		what = "prompt and output"
+	}
+	return b.toast(fmt.Sprintf("Copied %s to %s", what, method), false)
+}
+
+// openSelected suspends the TUI and opens the crumb file in $EDITOR
+func (b *Browser) openSelected() tea.Cmd {
+	c := b.selected()
+	if c == nil {
+		return nil
+	}
+
+	cmd := config.EditorCommand(c.Path)
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		if err != nil {
+			return saveErrorMsg{err: fmt.Errorf("failed to open editor: %w", err)}
+		}
+		return browserReloadMsg{}
+	})
+}
+
+// editSelected opens the selected crumb in the embedded capture form,
+// re-reading its file since listed crumbs come from the index cache
+func (b *Browser) editSelected() tea.Cmd {
+	selected := b.selected()
+	if selected == nil {
+		return nil
+	}
+	c, err := storage.ReadCrumb(selected.Path)
+	if err != nil {
+		return b.toast("Error: "+err.Error(), true)
+	}
+
+	form := NewEdit(b.config, b.storage, c)
+	form.embedded = true
+	updated, _ := form.Update(tea.WindowSizeMsg{Width: b.width, Height: b.height})
+	form = updated.(Model)
+	b.edit = &form
+	return form.Init()
+}
+
+func (b *Browser) toast(msg string, isError bool) tea.Cmd {
+	b.showToast = true
+	b.toastMsg = msg
+	b.isError = isError
+	return HideToastAfter(2 * time.Second)
+}
+
+// resize lays out the list and preview panes for the terminal size
+func (b *Browser) resize(width, height int) {
+	b.width = width
+	b.height = height
+	b.preview.Width = b.previewWidth()
+	b.preview.Height = b.paneHeight()
+	b.moveCursor(0)
+}
+
+func (b Browser) listWidth() int {
+	return max(b.width*2/5, 30)
+}
+
+// listRows is how many crumbs fit in the list pane (two lines each)
+func (b Browser) listRows() int {
+	return max(b.paneHeight()/2, 1)
+}
+
+func (b Browser) previewWidth() int {
+	return max(b.width-b.listWidth()-4, 20)
+}
+
+// paneHeight is the inner height of both panes (header, footer and
+// borders take the rest)
+func (b Browser) paneHeight() int {
+	return max(b.height-6, 5)
+}
+
+// updatePreview renders the selected crumb into the preview pane
+func (b *Browser) updatePreview() {
+	c := b.selected()
+	if c == nil {
+		b.preview.SetContent(helpStyle.Render("No crumbs match."))
+		return
+	}
+
+	width := b.previewWidth()
+	if b.renderedAt != width {
+		b.rendered = make(map[string]string)
+		b.renderedAt = width
+	}
+
+	content, ok := b.rendered[c.Path]
+	if !ok {
+		content = b.renderCrumb(c, width)
+		b.rendered[c.Path] = content
+	}
+	b.preview.SetContent(content)
+	b.preview.GotoTop()
+}
+
+// renderCrumb renders a crumb's metadata and sections with glamour
+func (b Browser) renderCrumb(c *storage.Crumb, width int) string {
+	var md strings.Builder
+	md.WriteString(fmt.Sprintf("# %s\n\n", c.Title))
+	md.WriteString(fmt.Sprintf("*%s*\n\n", strings.Join(crumbMeta(c), " · ")))
+	if g := c.Git; g != nil && g.Commit != "" {
+		ref := g.ShortCommit()
+		if url := g.CommitURL(); url != "" {
+			ref = fmt.Sprintf("[%s](%s)", ref, url)
+		}
+		if g.Branch != "" {
+			ref = g.Branch + " @ " + ref
+		}
+		md.WriteString(fmt.Sprintf("Captured on %s", ref))
+		if changed := g.Changed; len(changed) > 0 {
+			total := max(g.ChangedCount, len(changed))
+			more := ""
+			if len(changed) > 5 {
+				changed = changed[:5]
+			}
+			if total > len(changed) {
+				more = fmt.Sprintf(" and %d more", total-len(changed))
+			}
+			md.WriteString(fmt.Sprintf(" with uncommitted changes to %s%s", strings.Join(changed, ", "), more))
+		}
+		md.WriteString("\n\n")
+	}
+	if strings.TrimSpace(c.System) != "" {
+		md.WriteString("## System\n\n")
+		md.WriteString(c.System)
+		md.WriteString("\n\n")
+	}
+	md.WriteString("## Prompt\n\n")
+	md.WriteString(c.Prompt)
+	md.WriteString("\n\n")
+	if strings.TrimSpace(c.Output) != "" {
+		md.WriteString("## Output\n\n")
+		md.WriteString(c.Output)
+		md.WriteString("\n")
+	}
+
+	renderer, err := glamour.NewTermRenderer(
+		glamour.WithStandardStyle(b.glamStyle),
+		glamour.WithWordWrap(width-2),
+	)
+	if err != nil {
+		return md.String()
+	}
+	out, err := renderer.Render(md.String())
+	if err != nil {
+		return md.String()
+	}
+	return out
+}
+
+// crumbMeta lists the date, author, tool and tags of a crumb
+func crumbMeta(c *storage.Crumb) []string {
+	meta := []string{c.Date.Format("2006-01-02"), c.Author, c.Tool}
+	if len(c.Tags) > 0 {
+		meta = append(meta, strings.Join(c.Tags, ", "))
+	}
+	return meta
+}
+
+func (b Browser) View() string {
+	if b.edit != nil {
+		return b.edit.View()
+	}
+
+	var header strings.Builder
+	header.WriteString(titleStyle.Render("crumb"))
+	header.WriteString("  ")
+	header.WriteString(helpStyle.Render(fmt.Sprintf("%d of %d crumbs", len(b.visible), len(b.crumbs))))
+	if b.filtering || b.filter.Value() != "" {
+		header.WriteString("  ")
+		header.WriteString(b.filter.View())
+	}
+
+	list := browsePaneStyle.
+		Width(b.listWidth()).
+		Height(b.paneHeight()).
+		Render(b.renderList())
+	preview := browsePaneStyle.
+		Width(b.previewWidth()).
+		Height(b.paneHeight()).
+		Render(b.preview.View())
+
+	var footer string
+	switch {
+	case b.confirmDelete:
+		footer = browseConfirmStyle.Render(fmt.Sprintf("Delete %q? (y/N)", b.selected().Title))
+	case b.showToast:
+		footer = RenderToast(b.toastMsg, b.isError, b.width)
+	default:
+		footer = helpStyle.Render("↑/↓: select • /: filter • c/C: copy prompt/+output • o: open in $EDITOR • e: edit • d: delete • PgUp/PgDn: scroll • q: quit")
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left,
+		header.String(),
+		lipgloss.JoinHorizontal(lipgloss.Top, list, preview),
+		footer,
+	)
+}
+
+// renderList renders the visible window of the crumb list, two lines per crumb
+func (b Browser) renderList() string {
+	if len(b.visible) == 0 {
+		return helpStyle.Render("No crumbs found.")
+	}
+
+	rows := b.listRows()
+	width := b.listWidth() - 2
+	var lines []string
+	for i := b.offset; i < len(b.visible) && i < b.offset+rows; i++ {
+		c := b.visible[i]
+
+		marker, style := "  ", browseItemStyle
+		if i == b.cursor {
+			marker, style = "→ ", browseSelectedStyle
+		}
+
+		lines = append(lines, style.Render(clip(marker+c.Title, width)))
+		lines = append(lines, browseMetaStyle.Render(clip("  "+strings.Join(crumbMeta(c), " · "), width)))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// clip truncates s to width runes
+func clip(s string, width int) string {
+	runes := []rune(s)
+	if width <= 1 || len(runes) <= width {
+		return s
+	}
+	return string(runes[:width-1]) + "…"
+}
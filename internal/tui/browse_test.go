@@ -0,0 +1,153 @@
+package tui
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/adrg/xdg"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"crumb/internal/config"
+	"crumb/internal/storage"
+)
+
+func newTestBrowser(t *testing.T) Browser {
+	t.Helper()
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	xdg.Reload()
+	t.Cleanup(xdg.Reload)
+
+	dir := t.TempDir()
+	s := storage.NewMarkdownStorage(dir)
+	for i, title := range []string{"Fix flaky test", "Design review", "Refactor loader"} {
+		c := &storage.Crumb{
+			Title:  title,
+			Date:   time.Date(2024, 12, 1+i, 0, 0, 0, 0, time.UTC),
+			Author: "Ryan",
+			Tool:   "Cursor",
+			Prompt: "prompt for " + title,
+		}
+		if _, err := s.SaveCrumb(c); err != nil {
+			t.Fatalf("SaveCrumb failed: %v", err)
+		}
+	}
+
+	b, err := NewBrowser(config.DefaultConfig(), s)
+	if err != nil {
+		t.Fatalf("NewBrowser failed: %v", err)
+	}
+	updated, _ := b.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	return updated.(Browser)
+}
+
+func sendKeys(b Browser, keys ...string) Browser {
+	for _, k := range keys {
+		var msg tea.KeyMsg
+		switch k {
+		case "enter":
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		default:
+			msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+		}
+		updated, _ := b.Update(msg)
+		b = updated.(Browser)
+	}
+	return b
+}
+
+func TestBrowserListsNewestFirst(t *testing.T) {
+	b := newTestBrowser(t)
+
+	if len(b.visible) != 3 {
+		t.Fatalf("expected 3 crumbs, got %d", len(b.visible))
+	}
+	if b.selected().Title != "Refactor loader" {
+		t.Errorf("expected newest crumb selected, got %q", b.selected().Title)
+	}
+	if !strings.Contains(b.View(), "Design review") {
+		t.Error("expected list to render crumb titles")
+	}
+}
+
+func TestBrowserFilter(t *testing.T) {
+	b := newTestBrowser(t)
+	b = sendKeys(b, "/", "f", "l", "a", "k", "y", "enter")
+
+	if len(b.visible) != 1 || b.selected().Title != "Fix flaky test" {
+		t.Fatalf("expected filter to leave the flaky crumb, got %d", len(b.visible))
+	}
+	if b.filtering {
+		t.Error("expected enter to leave filter mode")
+	}
+}
+
+func TestBrowserDelete(t *testing.T) {
+	b := newTestBrowser(t)
+	path := b.selected().Path
+
+	b = sendKeys(b, "d", "n")
+	if _, err := os.Stat(path); err != nil {
+		t.Fatal("expected crumb to survive a declined delete")
+	}
+
+	b = sendKeys(b, "d", "y")
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("expected crumb file to be deleted")
+	}
+	if len(b.visible) != 2 {
+		t.Errorf("expected 2 crumbs after delete, got %d", len(b.visible))
+	}
+}
+
+func TestBrowserEditReadsFile(t *testing.T) {
+	b := newTestBrowser(t)
+	path := b.selected().Path
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// an unknown key the index cache can only store as a string
+	data = []byte(strings.Replace(string(data), "---\n", "---\nreviewed: 2024-05-01\n", 1))
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	// the second reload is served from the index cache
+	for i := 0; i < 2; i++ {
+		updated, _ := b.Update(browserReloadMsg{})
+		b = updated.(Browser)
+	}
+
+	b = sendKeys(b, "e")
+	if b.edit == nil {
+		t.Fatal("expected e to open the edit form")
+	}
+	if _, ok := b.edit.editing.Extra["reviewed"].(time.Time); !ok {
+		t.Errorf("expected reviewed to keep its YAML date type, got %T", b.edit.editing.Extra["reviewed"])
+	}
+}
+
+func TestBrowserEditKeepsAuthorSpelling(t *testing.T) {
+	b := newTestBrowser(t)
+	b.storage.Roster = storage.Roster{{Name: "Ryan Snodgrass", Aliases: []string{"Ryan"}}}
+	updated, _ := b.Update(browserReloadMsg{})
+	b = updated.(Browser)
+	if b.selected().Author != "Ryan Snodgrass" {
+		t.Fatalf("expected the roster name listed, got %q", b.selected().Author)
+	}
+	path := b.selected().Path
+
+	b = sendKeys(b, "e")
+	updated, _ = b.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	b = updated.(Browser)
+
+	c, err := storage.ReadCrumb(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Author != "Ryan" {
+		t.Errorf("expected the original author saved, got %q", c.Author)
+	}
+}
@@ -255,6 +255,18 @@ func (d Dropdown) Selected() string {
 	return ""
 }
 
+// Open expands the option list
+func (d *Dropdown) Open() {
+	d.open = true
+}
+
+// Close collapses the option list and clears the filter
+func (d *Dropdown) Close() {
+	d.open = false
+	d.filter = ""
+	d.filtered = []int{}
+}
+
 func (d *Dropdown) Focus() {
 	d.focused = true
 }
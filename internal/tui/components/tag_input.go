@@ -144,6 +144,12 @@ func (t TagInput) Tags() []string {
 	return t.tags
 }
 
+// SetTags replaces the selected tags, e.g. when editing an existing crumb
+func (t *TagInput) SetTags(tags []string) {
+	t.tags = append(make([]string, 0, len(tags)), tags...)
+	t.updateSuggestions()
+}
+
 func (t *TagInput) Focus() {
 	t.focused = true
 	t.updateSuggestions()
@@ -0,0 +1,208 @@
+package tui
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/adrg/xdg"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"crumb/internal/storage"
+)
+
+// autosaveInterval is how often an in-progress capture is written to disk
+const autosaveInterval = 5 * time.Second
+
+// draft is an unsaved capture form, autosaved so a crash or an accidental
+// quit doesn't lose it
+type draft struct {
+	Dir       string             `json:"dir"`
+	Prompt    string             `json:"prompt"`
+	Output    string             `json:"output"`
+	Title     string             `json:"title"`
+	Tool      string             `json:"tool"`
+	Tags      []string           `json:"tags,omitempty"`
+	Variables []storage.Variable `json:"variables,omitempty"`
+	SavedAt   time.Time          `json:"saved_at"`
+}
+
+// autosaveMsg triggers a periodic draft save
+type autosaveMsg struct{}
+
+func autosaveTick() tea.Cmd {
+	return tea.Tick(autosaveInterval, func(time.Time) tea.Msg {
+		return autosaveMsg{}
+	})
+}
+
+// draftPath returns the XDG state file holding the draft for a crumbs
+// directory, so captures in different repos don't clobber each other
+func draftPath(dir string) string {
+	sum := sha256.Sum256([]byte(dir))
+	path, err := xdg.StateFile(fmt.Sprintf("crumb/draft-%s.json", hex.EncodeToString(sum[:8])))
+	if err != nil {
+		return ""
+	}
+	return path
+}
+
+// loadDraft reads a saved draft, returning nil if there is none
+func loadDraft(path string) (*draft, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read draft: %w", err)
+	}
+
+	var d draft
+	if err := json.Unmarshal(data, &d); err != nil {
+		return nil, fmt.Errorf("failed to parse draft: %w", err)
+	}
+	if d.empty() {
+		return nil, nil
+	}
+	return &d, nil
+}
+
+// save writes the draft via a temp file so a crash mid-write can't leave a
+// truncated draft behind
+func (d draft) save(path string) error {
+	data, err := json.Marshal(d)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".draft-*.json")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func removeDraft(path string) {
+	if path != "" {
+		os.Remove(path)
+	}
+}
+
+// empty reports whether the draft has nothing worth recovering
+func (d draft) empty() bool {
+	return strings.TrimSpace(d.Prompt) == "" && strings.TrimSpace(d.Output) == ""
+}
+
+// same compares the user-editable fields of two drafts
+func (d draft) same(other draft) bool {
+	return d.Prompt == other.Prompt &&
+		d.Output == other.Output &&
+		d.Title == other.Title &&
+		d.Tool == other.Tool &&
+		slices.Equal(d.Tags, other.Tags)
+}
+
+// snapshot captures the current form contents
+func (m Model) snapshot() draft {
+	return draft{
+		Dir:       m.storage.Dir(),
+		Prompt:    m.prompt.Value(),
+		Output:    m.output.Value(),
+		Title:     m.title.Value(),
+		Tool:      m.toolSelect.Selected(),
+		Tags:      m.tags.Tags(),
+		Variables: m.variables,
+	}
+}
+
+// dirty reports whether the form has changed since it was opened or saved
+func (m Model) dirty() bool {
+	return !m.snapshot().same(m.baseline)
+}
+
+// markClean records the current form as the unchanged state
+func (m *Model) markClean() {
+	m.baseline = m.snapshot()
+	m.autosaved = m.baseline
+	removeDraft(m.draftPath)
+}
+
+// autosave writes the draft if it changed since the last autosave. Errors
+// are ignored: the draft is a safety net and the form is still usable.
+func (m *Model) autosave() {
+	if m.draftPath == "" {
+		return
+	}
+
+	d := m.snapshot()
+	if d.same(m.autosaved) {
+		return
+	}
+	m.autosaved = d
+
+	if !m.dirty() || d.empty() {
+		removeDraft(m.draftPath)
+		return
+	}
+	d.SavedAt = time.Now()
+	_ = d.save(m.draftPath)
+}
+
+// restoreDraft fills the form from a recovered draft
+func (m *Model) restoreDraft(d *draft) {
+	m.prompt.CharLimit = max(m.prompt.CharLimit, len([]rune(d.Prompt)))
+	m.output.CharLimit = max(m.output.CharLimit, len([]rune(d.Output)))
+	m.prompt.SetValue(d.Prompt)
+	m.output.SetValue(d.Output)
+	m.title.SetValue(d.Title)
+	m.selectTool(d.Tool)
+	m.tags.SetTags(d.Tags)
+	m.variables = d.Variables
+	m.setFocus(0)
+}
+
+// updateRecover answers the "recover draft?" prompt shown on launch
+func (m Model) updateRecover(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "y", "Y", "enter":
+		m.restoreDraft(m.recovered)
+		m.autosaved = m.snapshot()
+		m.recovered = nil
+	case "n", "N", "esc":
+		removeDraft(m.draftPath)
+		m.recovered = nil
+	}
+	return m, nil
+}
+
+// updateConfirmDiscard answers the "discard changes?" prompt shown on Esc
+func (m Model) updateConfirmDiscard(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	m.confirmDiscard = false
+
+	switch msg.String() {
+	case "ctrl+c":
+		m.autosave()
+		return m, tea.Quit
+	case "y", "Y":
+		removeDraft(m.draftPath)
+		return m, m.exit()
+	}
+	return m, nil
+}
@@ -0,0 +1,94 @@
+package tui
+
+import (
+	"os"
+	"testing"
+
+	"github.com/adrg/xdg"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"crumb/internal/config"
+	"crumb/internal/storage"
+)
+
+func newDraftTestModel(t *testing.T, dir string) Model {
+	t.Helper()
+	cfg := config.DefaultConfig()
+	cfg.OutputDir = dir
+	return New(cfg, storage.NewMarkdownStorage(dir), cfg.DefaultTool, "", false)
+}
+
+func TestDraftAutosaveAndRecover(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	xdg.Reload()
+	t.Cleanup(xdg.Reload)
+
+	dir := t.TempDir()
+	m := newDraftTestModel(t, dir)
+	if m.recovered != nil {
+		t.Fatal("expected no draft to recover")
+	}
+
+	m.prompt.SetValue("a long prompt")
+	m.output.SetValue("pasted output")
+	updated, _ := m.Update(autosaveMsg{})
+	m = updated.(Model)
+	if _, err := os.Stat(m.draftPath); err != nil {
+		t.Fatalf("expected draft file after autosave: %v", err)
+	}
+
+	// simulate a crash: a new form for the same directory offers the draft
+	m = newDraftTestModel(t, dir)
+	if m.recovered == nil {
+		t.Fatal("expected draft to be offered for recovery")
+	}
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	m = updated.(Model)
+	if m.prompt.Value() != "a long prompt" || m.output.Value() != "pasted output" {
+		t.Errorf("expected recovered fields, got %q/%q", m.prompt.Value(), m.output.Value())
+	}
+
+	// saving clears the draft
+	cmd := m.saveAndExit()
+	if _, ok := cmd().(saveSuccessMsg); !ok {
+		t.Fatalf("expected saveSuccessMsg, got %T", cmd())
+	}
+	if _, err := os.Stat(m.draftPath); !os.IsNotExist(err) {
+		t.Error("expected draft to be removed after save")
+	}
+}
+
+func TestEscConfirmsDiscard(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	xdg.Reload()
+	t.Cleanup(xdg.Reload)
+
+	m := newDraftTestModel(t, t.TempDir())
+
+	// a pristine form closes straight away
+	if _, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEsc}); cmd == nil {
+		t.Error("expected esc on an empty form to quit")
+	}
+
+	m.prompt.SetValue("unsaved")
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updated.(Model)
+	if !m.confirmDiscard || cmd != nil {
+		t.Fatal("expected esc on a dirty form to ask for confirmation")
+	}
+
+	updated, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	m = updated.(Model)
+	if m.confirmDiscard || cmd != nil || m.prompt.Value() != "unsaved" {
+		t.Error("expected 'n' to keep editing")
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updated.(Model)
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	if cmd == nil {
+		t.Error("expected 'y' to discard and quit")
+	}
+}
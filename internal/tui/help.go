@@ -8,7 +8,7 @@ import (
 
 const (
 	helpWidth  = 41
-	helpHeight = 13
+	helpHeight = 15
 )
 
 var (
@@ -38,8 +38,10 @@ func RenderHelpOverlay(width, height int) string {
 		{"Tab", "Next field"},
 		{"Shift+Tab", "Previous field"},
 		{"Ctrl+S", "Save and exit"},
+		{"Ctrl+E", "Edit field in $EDITOR"},
 		{"Esc", "Cancel and exit"},
 		{"/", "Open tool selector"},
+		{"Ctrl+O", "Start from a template"},
 		{"?", "Toggle this help"},
 	}
 
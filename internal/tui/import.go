@@ -0,0 +1,186 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"crumb/internal/importer"
+)
+
+// ImportPicker lists imported conversation turns and lets the user choose
+// which to save as crumbs, previewing the highlighted one.
+type ImportPicker struct {
+	source     string
+	candidates []importer.Candidate
+	chosen     []bool
+	cursor     int
+	offset     int // first visible row
+
+	confirmed bool
+
+	width  int
+	height int
+}
+
+// NewImportPicker builds a picker over candidates with none chosen
+func NewImportPicker(source string, candidates []importer.Candidate) ImportPicker {
+	return ImportPicker{
+		source:     source,
+		candidates: candidates,
+		chosen:     make([]bool, len(candidates)),
+		width:      80,
+		height:     24,
+	}
+}
+
+// Confirmed reports whether the user accepted the selection rather than
+// cancelling
+func (p ImportPicker) Confirmed() bool {
+	return p.confirmed
+}
+
+// Chosen returns the selected candidates in list order
+func (p ImportPicker) Chosen() []importer.Candidate {
+	var result []importer.Candidate
+	for i, c := range p.candidates {
+		if p.chosen[i] {
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+func (p ImportPicker) Init() tea.Cmd {
+	return nil
+}
+
+func (p ImportPicker) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		p.width = msg.Width
+		p.height = msg.Height
+		p.scroll()
+		return p, nil
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "esc", "q":
+			return p, tea.Quit
+		case "up", "k":
+			p.move(-1)
+		case "down", "j":
+			p.move(1)
+		case "pgup":
+			p.move(-p.listHeight())
+		case "pgdown":
+			p.move(p.listHeight())
+		case " ", "x":
+			if len(p.candidates) > 0 {
+				p.chosen[p.cursor] = !p.chosen[p.cursor]
+				p.move(1)
+			}
+		case "a":
+			// select all, or none when everything is already selected
+			all := true
+			for _, c := range p.chosen {
+				all = all && c
+			}
+			for i := range p.chosen {
+				p.chosen[i] = !all
+			}
+		case "enter":
+			// enter with nothing selected takes the highlighted turn
+			if len(p.Chosen()) == 0 && len(p.candidates) > 0 {
+				p.chosen[p.cursor] = true
+			}
+			p.confirmed = true
+			return p, tea.Quit
+		}
+	}
+	return p, nil
+}
+
+func (p *ImportPicker) move(delta int) {
+	if len(p.candidates) == 0 {
+		return
+	}
+	p.cursor = max(0, min(len(p.candidates)-1, p.cursor+delta))
+	p.scroll()
+}
+
+// scroll keeps the cursor inside the visible rows
+func (p *ImportPicker) scroll() {
+	rows := p.listHeight()
+	if p.cursor < p.offset {
+		p.offset = p.cursor
+	}
+	if p.cursor >= p.offset+rows {
+		p.offset = p.cursor - rows + 1
+	}
+}
+
+// listHeight is the number of rows left for the list after the preview
+func (p ImportPicker) listHeight() int {
+	return max(p.height/2-3, 3)
+}
+
+func (p ImportPicker) View() string {
+	var b strings.Builder
+	b.WriteString(titleStyle.Render("crumb import"))
+	b.WriteString("  ")
+	b.WriteString(helpStyle.Render(fmt.Sprintf("%s · %d selected of %d", p.source, len(p.Chosen()), len(p.candidates))))
+	b.WriteString("\n\n")
+
+	if len(p.candidates) == 0 {
+		b.WriteString(helpStyle.Render("Nothing new to import."))
+		b.WriteString("\n")
+		return b.String()
+	}
+
+	end := min(p.offset+p.listHeight(), len(p.candidates))
+	for i := p.offset; i < end; i++ {
+		c := p.candidates[i]
+		box := "[ ]"
+		if p.chosen[i] {
+			box = "[x]"
+		}
+		date := ""
+		if !c.Time.IsZero() {
+			date = c.Time.Local().Format("2006-01-02 15:04") + "  "
+		}
+		line := clip(fmt.Sprintf("%s %s%s", box, date, c.Title()), p.width-2)
+		if i == p.cursor {
+			b.WriteString(browseSelectedStyle.Render(line))
+		} else {
+			b.WriteString(browseItemStyle.Render(line))
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(p.preview())
+	b.WriteString("\n")
+	b.WriteString(helpStyle.Render("↑/↓: move • Space: select • a: all/none • Enter: import • Esc: cancel"))
+	return b.String()
+}
+
+// preview shows the start of the highlighted prompt and output
+func (p ImportPicker) preview() string {
+	c := p.candidates[p.cursor]
+	lines := append([]string{labelStyle.Render("Prompt")}, strings.Split(c.Prompt, "\n")...)
+	if c.Output != "" {
+		lines = append(lines, "", labelStyle.Render("Output"))
+		lines = append(lines, strings.Split(c.Output, "\n")...)
+	}
+
+	rows := max(p.height-p.listHeight()-8, 3)
+	if len(lines) > rows {
+		lines = append(lines[:rows], "…")
+	}
+	for i, line := range lines {
+		lines[i] = clip(line, p.width-6)
+	}
+	return varPreviewStyle.Width(max(p.width-4, 20)).Render(strings.Join(lines, "\n"))
+}
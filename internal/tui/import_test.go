@@ -0,0 +1,47 @@
+package tui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"crumb/internal/importer"
+)
+
+func TestImportPickerSelection(t *testing.T) {
+	p := NewImportPicker("Claude Code", []importer.Candidate{
+		{ID: "a", Prompt: "first"},
+		{ID: "b", Prompt: "second"},
+		{ID: "c", Prompt: "third"},
+	})
+
+	// space toggles and moves down, so this picks the first and third
+	for _, msg := range []tea.KeyMsg{
+		{Type: tea.KeySpace, Runes: []rune{' '}},
+		{Type: tea.KeyDown},
+		{Type: tea.KeySpace, Runes: []rune{' '}},
+		{Type: tea.KeyEnter},
+	} {
+		updated, _ := p.Update(msg)
+		p = updated.(ImportPicker)
+	}
+
+	if !p.Confirmed() {
+		t.Fatal("expected enter to confirm")
+	}
+	chosen := p.Chosen()
+	if len(chosen) != 2 || chosen[0].ID != "a" || chosen[1].ID != "c" {
+		t.Errorf("expected first and third chosen, got %+v", chosen)
+	}
+}
+
+func TestImportPickerEnterTakesHighlighted(t *testing.T) {
+	p := NewImportPicker("Claude Code", []importer.Candidate{{ID: "a"}, {ID: "b"}})
+	updated, _ := p.Update(tea.KeyMsg{Type: tea.KeyDown})
+	updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	p = updated.(ImportPicker)
+
+	if chosen := p.Chosen(); len(chosen) != 1 || chosen[0].ID != "b" {
+		t.Errorf("expected the highlighted turn, got %+v", chosen)
+	}
+}
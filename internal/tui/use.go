@@ -0,0 +1,198 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"crumb/internal/storage"
+)
+
+// maxPreviewLines bounds the rendered prompt shown under the form
+const maxPreviewLines = 12
+
+var (
+	varDescStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color(Overlay))
+
+	varPreviewStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color(Text)).
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(lipgloss.Color(Surface)).
+			Padding(0, 1)
+)
+
+// VarForm asks for the values of a template crumb's variables, showing a
+// live preview of the rendered prompt.
+type VarForm struct {
+	crumb  *storage.Crumb
+	vars   []storage.Variable
+	inputs []textinput.Model
+	focus  int
+
+	submitted bool
+	err       string
+
+	width  int
+	height int
+}
+
+// NewVarForm builds a form for the crumb's variables, prefilled from
+// values and then from each variable's default.
+func NewVarForm(crumb *storage.Crumb, values map[string]string) VarForm {
+	vars := crumb.TemplateVariables()
+	inputs := make([]textinput.Model, len(vars))
+	for i, v := range vars {
+		input := textinput.New()
+		input.Prompt = ""
+		input.CharLimit = 0
+		input.Width = 60
+		if v.Default != "" {
+			input.Placeholder = v.Default
+		}
+		if value, ok := values[v.Name]; ok {
+			input.SetValue(value)
+		} else {
+			input.SetValue(v.Default)
+		}
+		inputs[i] = input
+	}
+
+	f := VarForm{
+		crumb:  crumb,
+		vars:   vars,
+		inputs: inputs,
+		width:  80,
+		height: 24,
+	}
+	f.setFocus(0)
+	return f
+}
+
+// Submitted reports whether the form was completed rather than cancelled
+func (f VarForm) Submitted() bool {
+	return f.submitted
+}
+
+// Values returns the entered value of every variable
+func (f VarForm) Values() map[string]string {
+	values := make(map[string]string, len(f.vars))
+	for i, v := range f.vars {
+		values[v.Name] = f.inputs[i].Value()
+	}
+	return values
+}
+
+func (f VarForm) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+func (f VarForm) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		f.width = msg.Width
+		f.height = msg.Height
+		for i := range f.inputs {
+			f.inputs[i].Width = max(msg.Width-8, 20)
+		}
+		return f, nil
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "esc":
+			return f, tea.Quit
+		case "tab", "down":
+			f.setFocus(f.focus + 1)
+			return f, nil
+		case "shift+tab", "up":
+			f.setFocus(f.focus - 1)
+			return f, nil
+		case "enter":
+			if f.focus < len(f.inputs)-1 {
+				f.setFocus(f.focus + 1)
+				return f, nil
+			}
+			return f.submit()
+		case "ctrl+s":
+			return f.submit()
+		}
+	}
+
+	if len(f.inputs) == 0 {
+		return f, nil
+	}
+
+	var cmd tea.Cmd
+	f.inputs[f.focus], cmd = f.inputs[f.focus].Update(msg)
+	f.err = ""
+	return f, cmd
+}
+
+// submit finishes the form unless a variable without a default is empty
+func (f VarForm) submit() (tea.Model, tea.Cmd) {
+	for i, v := range f.vars {
+		if f.inputs[i].Value() == "" && v.Default == "" {
+			f.err = fmt.Sprintf("%s is required", v.Name)
+			f.setFocus(i)
+			return f, nil
+		}
+	}
+
+	f.submitted = true
+	return f, tea.Quit
+}
+
+func (f *VarForm) setFocus(i int) {
+	if len(f.inputs) == 0 {
+		return
+	}
+	f.focus = (i + len(f.inputs)) % len(f.inputs)
+	for j := range f.inputs {
+		if j == f.focus {
+			f.inputs[j].Focus()
+		} else {
+			f.inputs[j].Blur()
+		}
+	}
+}
+
+func (f VarForm) View() string {
+	var b strings.Builder
+	b.WriteString(titleStyle.Render("crumb use"))
+	b.WriteString("  ")
+	b.WriteString(helpStyle.Render(f.crumb.Title))
+	b.WriteString("\n\n")
+
+	for i, v := range f.vars {
+		label := labelStyle
+		if i == f.focus {
+			label = focusedLabelStyle
+		}
+		b.WriteString(label.Render(v.Name))
+		if v.Description != "" {
+			b.WriteString("  ")
+			b.WriteString(varDescStyle.Render(v.Description))
+		}
+		b.WriteString("\n")
+		b.WriteString(f.inputs[i].View())
+		b.WriteString("\n\n")
+	}
+
+	preview := storage.RenderPlaceholders(f.crumb.Prompt, f.Values())
+	lines := strings.Split(preview, "\n")
+	if len(lines) > maxPreviewLines {
+		lines = append(lines[:maxPreviewLines], "…")
+	}
+	b.WriteString(varPreviewStyle.Width(max(f.width-4, 20)).Render(strings.Join(lines, "\n")))
+	b.WriteString("\n")
+
+	if f.err != "" {
+		b.WriteString(ErrorStyle.Render(f.err))
+		b.WriteString("\n")
+	}
+	b.WriteString(helpStyle.Render("Tab/↑/↓: next field • Enter: next/done • Ctrl+S: done • Esc: cancel"))
+	return b.String()
+}
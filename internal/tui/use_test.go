@@ -0,0 +1,45 @@
+package tui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"crumb/internal/storage"
+)
+
+func TestVarFormRequiresValues(t *testing.T) {
+	crumb := &storage.Crumb{
+		Title:     "Review",
+		Variables: []storage.Variable{{Name: "concern", Default: "security"}},
+		Prompt:    "Review {{file}} for {{concern}}",
+	}
+
+	f := NewVarForm(crumb, nil)
+
+	// concern is prefilled from its default; file is empty and required
+	updated, _ := f.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	f = updated.(VarForm)
+	if f.Submitted() {
+		t.Fatal("expected submit to be refused with an empty required variable")
+	}
+	if f.vars[f.focus].Name != "file" {
+		t.Errorf("expected focus on the missing variable, got %s", f.vars[f.focus].Name)
+	}
+
+	updated, _ = f.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("main.go")})
+	f = updated.(VarForm)
+	updated, _ = f.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	f = updated.(VarForm)
+	if !f.Submitted() {
+		t.Fatal("expected form to submit")
+	}
+
+	got, err := crumb.Render(f.Values())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if got != "Review main.go for security" {
+		t.Errorf("unexpected render: %q", got)
+	}
+}